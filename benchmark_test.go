@@ -42,6 +42,17 @@ func BenchmarkDo_ErrorWithFinally(b *testing.B) {
 	}
 }
 
+func BenchmarkDo_TryCtx(b *testing.B) {
+	b.ReportAllocs()
+	tc := New()
+	try := func(ctx context.Context) error { return nil }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := tc.TryCtx(try).Do()
+		runtime.KeepAlive(err)
+	}
+}
+
 func BenchmarkDo_Panic(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -318,3 +329,29 @@ func BenchmarkHotpath_Loop(b *testing.B) {
 		pool.Put(tc)
 	}
 }
+
+func BenchmarkTypedBlock_PoolReuse(b *testing.B) {
+	b.ReportAllocs()
+	pool := &sync.Pool{
+		New: func() any { return NewTyped[int]() },
+	}
+	for i := 0; i < b.N; i++ {
+		tb := pool.Get().(*TypedBlock[int])
+		tb.Try(func() (int, error) { return 1, nil })
+		tb.Do()
+		tb.Reset()
+		pool.Put(tb)
+	}
+}
+
+func BenchmarkTypedBlock_TryCtx(b *testing.B) {
+	b.ReportAllocs()
+	tb := NewTyped[int]()
+	try := func(ctx context.Context) (int, error) { return 1, nil }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, err := tb.TryCtx(try).Do()
+		runtime.KeepAlive(v)
+		runtime.KeepAlive(err)
+	}
+}
@@ -0,0 +1,265 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 表示熔断器处于打开状态（或半开状态下试探请求已满），try 被跳过
+var ErrCircuitOpen = errors.New("gotrycatch: circuit breaker is open")
+
+// CircuitBreaker 定义熔断器，Do 在执行 try 之前调用 Allow，并在被允许的执行结束后调用 Record
+// Allow 返回的 generation 标识放行时熔断器所处的代，Do 将其原样传给 Record，
+// 实现据此忽略在状态变化之前放行、之后才结束的执行（例如关闭状态下放行的慢请求不应被当作半开状态的试探）。
+// 实现需要是并发安全的，同一个熔断器通常被多个块共享
+type CircuitBreaker interface {
+	Allow() (generation uint64, err error) // 返回非 nil 错误时跳过 try，该错误按普通错误经过 catch 与 finally
+	Record(generation uint64, err error)   // 报告被允许的执行的结果，err 为 try 的最终错误，panic 时为转换得到的错误
+}
+
+// WithCircuitBreaker 为块设置熔断器
+// 熔断器打开时 Do 跳过 try，通过正常的 catch / finally 流程返回 ErrCircuitOpen；配置重试时整个重试过程只计为一次执行
+func WithCircuitBreaker(cb CircuitBreaker) Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().breaker = cb
+	}
+}
+
+// recordBreaker 向熔断器报告本次执行的结果；panic 命中重新抛出策略时没有转换得到的错误，以 *PanicError 代替
+func (tc *TryCatchBlock) recordBreaker(generation uint64, err error, panicVal any) {
+	if err == nil && panicVal != nil {
+		err = &PanicError{Value: panicVal, Name: tc.name}
+	}
+	tc.opts.breaker.Record(generation, err)
+}
+
+// BreakerState 表示熔断器的状态
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // 关闭：请求正常通过，统计失败比例
+	BreakerOpen                         // 打开：拒绝所有请求，冷却时间结束后进入半开
+	BreakerHalfOpen                     // 半开：只允许有限个试探请求，全部成功后关闭，任一失败重新打开
+)
+
+// String 返回熔断器状态的名称
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig 定义内置熔断器的参数，零值字段使用默认值
+type BreakerConfig struct {
+	FailureRatio   float64                                  // 触发熔断的失败比例，默认 0.5
+	MinRequests    int                                      // 统计周期内判断失败比例所需的最少请求数，默认 10
+	Interval       time.Duration                            // 关闭状态下清空统计的周期，0 表示只在状态变化时清空
+	Cooldown       time.Duration                            // 打开状态的持续时间，结束后进入半开，默认 30s
+	HalfOpenProbes int                                      // 半开状态允许的试探请求数，默认 1
+	IsFailure      func(err error) bool                     // 判断结果是否计为失败，默认非 nil 且不是 context.Canceled 的错误计为失败
+	OnStateChange  func(name string, from, to BreakerState) // 状态变化时调用，在熔断器的锁之外执行
+}
+
+// withDefaults 返回填充默认值后的配置
+func (c BreakerConfig) withDefaults() BreakerConfig {
+	if c.FailureRatio <= 0 {
+		c.FailureRatio = 0.5
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = 30 * time.Second
+	}
+	if c.HalfOpenProbes <= 0 {
+		c.HalfOpenProbes = 1
+	}
+	if c.IsFailure == nil {
+		c.IsFailure = isBreakerFailure
+	}
+	return c
+}
+
+// isBreakerFailure 是默认的失败判断：调用方主动取消不应计为下游故障
+func isBreakerFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// Breaker 是内置的熔断器，实现 CircuitBreaker
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+	now  func() time.Time // 当前时间，测试中可替换
+
+	mu          sync.Mutex
+	state       BreakerState
+	generation  uint64    // 每次状态变化时递增，用于识别旧状态下放行的执行
+	requests    int       // 关闭状态下当前统计周期的请求数
+	failures    int       // 关闭状态下当前统计周期的失败数
+	windowEnd   time.Time // 关闭状态下当前统计周期的结束时间，Interval 为 0 时为零值
+	openUntil   time.Time // 打开状态的结束时间
+	probes      int       // 半开状态下已放行的试探请求数
+	probeOK     int       // 半开状态下已成功的试探请求数
+	transitions []func()  // 待在锁外执行的状态变化回调
+}
+
+// NewBreaker 创建内置熔断器，name 用于 OnStateChange 回调
+func NewBreaker(name string, cfg BreakerConfig) *Breaker {
+	b := &Breaker{name: name, cfg: cfg.withDefaults(), now: time.Now}
+	b.resetWindow(b.now())
+	return b
+}
+
+// Name 返回熔断器的名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State 返回熔断器当前的状态
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	b.advance(b.now())
+	state := b.state
+	b.mu.Unlock()
+	b.notify()
+	return state
+}
+
+// Allow 实现 CircuitBreaker，打开状态或半开状态下试探请求已满时返回 ErrCircuitOpen
+func (b *Breaker) Allow() (uint64, error) {
+	b.mu.Lock()
+	b.advance(b.now())
+	generation := b.generation
+	var err error
+	switch b.state {
+	case BreakerOpen:
+		err = ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			err = ErrCircuitOpen
+		} else {
+			b.probes++
+		}
+	}
+	b.mu.Unlock()
+	b.notify()
+	return generation, err
+}
+
+// Record 实现 CircuitBreaker，根据执行结果更新统计与状态
+// 放行之后熔断器的状态已经变化时，结果属于旧的一代，被忽略
+func (b *Breaker) Record(generation uint64, err error) {
+	failed := b.cfg.IsFailure(err)
+	b.mu.Lock()
+	now := b.now()
+	b.advance(now)
+	if generation != b.generation {
+		b.mu.Unlock()
+		b.notify()
+		return
+	}
+	switch b.state {
+	case BreakerClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRatio {
+			b.setState(BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			b.setState(BreakerOpen, now)
+			break
+		}
+		b.probeOK++
+		if b.probeOK >= b.cfg.HalfOpenProbes {
+			b.setState(BreakerClosed, now)
+		}
+	}
+	b.mu.Unlock()
+	b.notify()
+}
+
+// advance 处理随时间发生的状态变化：冷却结束进入半开，统计周期结束清空统计；调用方需持有锁
+func (b *Breaker) advance(now time.Time) {
+	switch b.state {
+	case BreakerOpen:
+		if !now.Before(b.openUntil) {
+			b.setState(BreakerHalfOpen, now)
+		}
+	case BreakerClosed:
+		if !b.windowEnd.IsZero() && !now.Before(b.windowEnd) {
+			b.resetWindow(now)
+		}
+	}
+}
+
+// setState 切换状态并清空统计，状态变化回调被推迟到锁外执行；调用方需持有锁
+func (b *Breaker) setState(to BreakerState, now time.Time) {
+	from := b.state
+	b.state = to
+	b.generation++
+	b.probes, b.probeOK = 0, 0
+	b.resetWindow(now)
+	if to == BreakerOpen {
+		b.openUntil = now.Add(b.cfg.Cooldown)
+	}
+	if fn := b.cfg.OnStateChange; fn != nil {
+		name := b.name
+		b.transitions = append(b.transitions, func() { fn(name, from, to) })
+	}
+}
+
+// resetWindow 开始新的统计周期；调用方需持有锁
+func (b *Breaker) resetWindow(now time.Time) {
+	b.requests, b.failures = 0, 0
+	if b.cfg.Interval > 0 {
+		b.windowEnd = now.Add(b.cfg.Interval)
+	} else {
+		b.windowEnd = time.Time{}
+	}
+}
+
+// notify 在锁外执行待处理的状态变化回调
+func (b *Breaker) notify() {
+	if b.cfg.OnStateChange == nil {
+		return
+	}
+	b.mu.Lock()
+	transitions := b.transitions
+	b.transitions = nil
+	b.mu.Unlock()
+	for _, fn := range transitions {
+		fn()
+	}
+}
+
+// BreakerRegistry 按名称管理共享的熔断器，名称相同的块可以共用同一个熔断器
+type BreakerRegistry struct {
+	cfg      BreakerConfig
+	breakers sync.Map // name -> *Breaker
+}
+
+// NewBreakerRegistry 创建熔断器注册表，新建的熔断器都使用 cfg
+func NewBreakerRegistry(cfg BreakerConfig) *BreakerRegistry {
+	return &BreakerRegistry{cfg: cfg}
+}
+
+// Get 返回名称对应的熔断器，不存在时按注册表的配置创建
+func (r *BreakerRegistry) Get(name string) *Breaker {
+	if b, ok := r.breakers.Load(name); ok {
+		return b.(*Breaker)
+	}
+	b, _ := r.breakers.LoadOrStore(name, NewBreaker(name, r.cfg))
+	return b.(*Breaker)
+}
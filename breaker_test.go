@@ -0,0 +1,262 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock 是可手动推进的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(cfg BreakerConfig) (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b := NewBreaker("test", cfg)
+	b.now = clock.Now
+	b.resetWindow(clock.Now())
+	return b, clock
+}
+
+// record 放行一次执行并报告结果
+func record(t *testing.T, b *Breaker, err error) {
+	t.Helper()
+	generation, allowErr := b.Allow()
+	assert.NoError(t, allowErr)
+	b.Record(generation, err)
+}
+
+func TestBreaker_OpensOnFailureRatio(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{FailureRatio: 0.5, MinRequests: 4})
+	boom := errors.New("boom")
+
+	for _, err := range []error{nil, boom, nil} {
+		record(t, b, err)
+	}
+	assert.Equal(t, BreakerClosed, b.State(), "below MinRequests")
+
+	record(t, b, boom)
+	assert.Equal(t, BreakerOpen, b.State())
+	_, err := b.Allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestBreaker_HalfOpenProbes(t *testing.T) {
+	b, clock := newTestBreaker(BreakerConfig{MinRequests: 1, Cooldown: time.Second, HalfOpenProbes: 2})
+	record(t, b, errors.New("boom"))
+	assert.Equal(t, BreakerOpen, b.State())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	first, err := b.Allow()
+	assert.NoError(t, err)
+	second, err := b.Allow()
+	assert.NoError(t, err)
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen, "probe limit reached")
+
+	b.Record(first, nil)
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	b.Record(second, nil)
+	assert.Equal(t, BreakerClosed, b.State())
+	_, err = b.Allow()
+	assert.NoError(t, err)
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	b, clock := newTestBreaker(BreakerConfig{MinRequests: 1, Cooldown: time.Second})
+	record(t, b, errors.New("boom"))
+
+	clock.Advance(time.Second)
+	record(t, b, errors.New("still broken"))
+	assert.Equal(t, BreakerOpen, b.State())
+
+	clock.Advance(500 * time.Millisecond)
+	_, err := b.Allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen, "cool-down restarted")
+}
+
+func TestBreaker_IntervalResetsCounts(t *testing.T) {
+	b, clock := newTestBreaker(BreakerConfig{FailureRatio: 0.5, MinRequests: 2, Interval: time.Minute})
+	record(t, b, errors.New("boom"))
+
+	clock.Advance(time.Minute)
+	record(t, b, errors.New("boom"))
+	assert.Equal(t, BreakerClosed, b.State(), "first failure belongs to an expired window")
+
+	record(t, b, errors.New("boom"))
+	assert.Equal(t, BreakerOpen, b.State())
+}
+
+func TestBreaker_CancelledNotFailure(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{MinRequests: 1})
+	record(t, b, context.Canceled)
+	assert.Equal(t, BreakerClosed, b.State())
+
+	record(t, b, context.DeadlineExceeded)
+	assert.Equal(t, BreakerOpen, b.State())
+}
+
+func TestBreaker_OnStateChange(t *testing.T) {
+	var transitions []string
+	b, clock := newTestBreaker(BreakerConfig{
+		MinRequests: 1,
+		Cooldown:    time.Second,
+		OnStateChange: func(name string, from, to BreakerState) {
+			transitions = append(transitions, name+":"+from.String()+"->"+to.String())
+		},
+	})
+
+	record(t, b, errors.New("boom"))
+	clock.Advance(time.Second)
+	record(t, b, nil)
+
+	assert.Equal(t, []string{
+		"test:closed->open",
+		"test:open->half-open",
+		"test:half-open->closed",
+	}, transitions)
+}
+
+func TestBreaker_IgnoresStaleGeneration(t *testing.T) {
+	b, clock := newTestBreaker(BreakerConfig{MinRequests: 1, Cooldown: time.Second})
+
+	// 关闭状态下放行的慢请求
+	slow, err := b.Allow()
+	assert.NoError(t, err)
+
+	// 另一个请求的失败打开熔断器，冷却结束后进入半开
+	record(t, b, errors.New("boom"))
+	clock.Advance(time.Second)
+	assert.Equal(t, BreakerHalfOpen, b.State())
+
+	b.Record(slow, nil)
+	assert.Equal(t, BreakerHalfOpen, b.State(), "a result admitted while closed is not a half-open probe")
+
+	probe, err := b.Allow()
+	assert.NoError(t, err, "the stale result should not consume the probe")
+	b.Record(probe, nil)
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestWithCircuitBreaker_Do(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{MinRequests: 2})
+	boom := errors.New("boom")
+	opts := []Option{WithCircuitBreaker(b)}
+
+	_ = NewWithOptions(opts...).Try(func() error { return boom }).Do()
+	_ = NewWithOptions(opts...).Try(func() error { panic("boom") }).Do()
+	assert.Equal(t, BreakerOpen, b.State(), "errors and panics both count as failures")
+
+	var tryCalled, finallyCalled bool
+	var caught error
+	err := NewWithOptions(opts...).
+		Try(func() error {
+			tryCalled = true
+			return nil
+		}).
+		Catch(func(err error) { caught = err }).
+		Finally(func() { finallyCalled = true }).
+		Do()
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, caught, ErrCircuitOpen)
+	assert.False(t, tryCalled)
+	assert.True(t, finallyCalled)
+}
+
+func TestWithCircuitBreaker_RepanicCounts(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{MinRequests: 1})
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithCircuitBreaker(b), WithRepanicPolicy(func(any) bool { return true })).
+			Try(func() error { panic("fatal") }).
+			Do()
+	})
+	assert.Equal(t, BreakerOpen, b.State())
+}
+
+func TestWithCircuitBreaker_RetryCountsOnce(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{MinRequests: 2})
+
+	_ = NewWithOptions(WithCircuitBreaker(b), WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() error { return errors.New("boom") }).
+		Do()
+
+	assert.Equal(t, BreakerClosed, b.State(), "a retried execution is recorded once")
+}
+
+func TestWithCircuitBreaker_CancelledNotRecorded(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{MinRequests: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = NewWithOptions(WithContext(ctx), WithCircuitBreaker(b)).Try(func() error { return nil }).Do()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	assert.Equal(t, 0, b.requests, "execution cancelled before Allow is not recorded")
+}
+
+func TestBreakerRegistry_SharedByName(t *testing.T) {
+	reg := NewBreakerRegistry(BreakerConfig{MinRequests: 1})
+
+	assert.Same(t, reg.Get("payments"), reg.Get("payments"))
+	assert.NotSame(t, reg.Get("payments"), reg.Get("inventory"))
+	assert.Equal(t, "payments", reg.Get("payments").Name())
+
+	_ = NewWithOptions(WithName("payments"), WithCircuitBreaker(reg.Get("payments"))).
+		Try(func() error { return errors.New("boom") }).
+		Do()
+
+	err := NewWithOptions(WithName("payments"), WithCircuitBreaker(reg.Get("payments"))).
+		Try(func() error { return nil }).
+		Do()
+	assert.ErrorIs(t, err, ErrCircuitOpen, "blocks with the same name share the breaker")
+}
+
+func TestBreaker_Concurrent(t *testing.T) {
+	b := NewBreaker("concurrent", BreakerConfig{MinRequests: 1000})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = NewWithOptions(WithCircuitBreaker(b)).
+				Try(func() error {
+					if i%2 == 0 {
+						return errors.New("boom")
+					}
+					return nil
+				}).
+				Do()
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestBreakerState_String(t *testing.T) {
+	assert.Equal(t, "closed", BreakerClosed.String())
+	assert.Equal(t, "open", BreakerOpen.String())
+	assert.Equal(t, "half-open", BreakerHalfOpen.String())
+	assert.Equal(t, "unknown", BreakerState(9).String())
+}
@@ -0,0 +1,91 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBulkheadFull 表示隔板的并发名额已满且无法排队（队列已满或等待超时），try 被跳过
+var ErrBulkheadFull = errors.New("gotrycatch: bulkhead is full")
+
+// Bulkhead 限制共享它的块同时执行 try 的数量，可以被多个块并发共享
+type Bulkhead struct {
+	slots    chan struct{} // 并发名额
+	maxQueue int64         // 最多排队等待的数量
+	maxWait  time.Duration // 排队等待的最长时间，0 表示只受 context 限制
+	waiting  atomic.Int64  // 当前排队等待的数量
+}
+
+// NewBulkhead 创建隔板：最多 maxConcurrent 个 try 同时执行，名额已满时最多 maxQueue 个调用排队等待，
+// 每个最多等待 maxWait（0 表示只受 context 限制）；maxConcurrent 小于 1 时按 1 处理
+func NewBulkhead(maxConcurrent, maxQueue int, maxWait time.Duration) *Bulkhead {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxQueue < 0 {
+		maxQueue = 0
+	}
+	return &Bulkhead{
+		slots:    make(chan struct{}, maxConcurrent),
+		maxQueue: int64(maxQueue),
+		maxWait:  maxWait,
+	}
+}
+
+// Acquire 获取一个名额：名额已满时排队等待，队列已满或等待超时返回 ErrBulkheadFull，ctx 先结束时返回 ctx.Err()
+// 成功后必须调用 Release 归还名额
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.waiting.Add(1) > b.maxQueue {
+		b.waiting.Add(-1)
+		return ErrBulkheadFull
+	}
+	defer b.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if b.maxWait > 0 {
+		timer := time.NewTimer(b.maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release 归还 Acquire 获取的名额
+func (b *Bulkhead) Release() {
+	<-b.slots
+}
+
+// InFlight 返回当前占用的名额数
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Waiting 返回当前排队等待的数量
+func (b *Bulkhead) Waiting() int {
+	return int(b.waiting.Load())
+}
+
+// WithBulkhead 为块设置隔板
+// 名额在 try 执行前获取（早于熔断器检查，被隔板拒绝不计入熔断统计），在 try 结束后、catch 之前归还，try 发生 panic 时同样归还；
+// 无法获取名额时 Do 跳过 try，通过正常的 catch / finally 流程返回 ErrBulkheadFull 或 ctx.Err()。
+// 配置重试时整个重试过程只占用一个名额；强制截止模式下被放弃的 try 继续占用名额，直到其在后台真正结束
+func WithBulkhead(b *Bulkhead) Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().bulkhead = b
+	}
+}
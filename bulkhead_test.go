@@ -0,0 +1,204 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitFor 轮询等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBulkhead_LimitsConcurrency(t *testing.T) {
+	b := NewBulkhead(2, 10, 0)
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := NewWithOptions(WithBulkhead(b)).
+				Try(func() error {
+					n := running.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					running.Add(-1)
+					return nil
+				}).
+				Do()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Equal(t, 0, b.InFlight())
+	assert.Equal(t, 0, b.Waiting())
+}
+
+func TestBulkhead_QueueFull(t *testing.T) {
+	b := NewBulkhead(1, 0, 0)
+	release := make(chan struct{})
+	h := Go(func() error {
+		<-release
+		return nil
+	}, WithBulkhead(b))
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	var tryCalled, finallyCalled bool
+	var caught error
+	err := NewWithOptions(WithBulkhead(b)).
+		Try(func() error {
+			tryCalled = true
+			return nil
+		}).
+		Catch(func(err error) { caught = err }).
+		Finally(func() { finallyCalled = true }).
+		Do()
+
+	assert.ErrorIs(t, err, ErrBulkheadFull)
+	assert.ErrorIs(t, caught, ErrBulkheadFull)
+	assert.False(t, tryCalled)
+	assert.True(t, finallyCalled)
+
+	close(release)
+	assert.NoError(t, h.Wait())
+	assert.Equal(t, 0, b.InFlight())
+}
+
+func TestBulkhead_MaxWait(t *testing.T) {
+	b := NewBulkhead(1, 1, 20*time.Millisecond)
+	assert.NoError(t, b.Acquire(context.Background()))
+	defer b.Release()
+
+	start := time.Now()
+	err := b.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrBulkheadFull)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, 0, b.Waiting())
+}
+
+func TestBulkhead_WaitsForSlot(t *testing.T) {
+	b := NewBulkhead(1, 1, time.Second)
+	assert.NoError(t, b.Acquire(context.Background()))
+
+	go func() {
+		waitFor(t, func() bool { return b.Waiting() == 1 })
+		b.Release()
+	}()
+
+	assert.NoError(t, b.Acquire(context.Background()))
+	b.Release()
+}
+
+func TestBulkhead_ContextCancelled(t *testing.T) {
+	b := NewBulkhead(1, 1, 0)
+	assert.NoError(t, b.Acquire(context.Background()))
+	defer b.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := NewWithOptions(WithContext(ctx), WithBulkhead(b)).
+		Try(func() error { return nil }).
+		Do()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBulkhead_ReleasedOnPanic(t *testing.T) {
+	b := NewBulkhead(1, 0, 0)
+
+	err := NewWithOptions(WithBulkhead(b)).Try(func() error { panic("boom") }).Do()
+	assert.ErrorAs(t, err, new(*PanicError))
+	assert.Equal(t, 0, b.InFlight(), "slot must not leak on panic")
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithBulkhead(b)).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+	assert.Equal(t, 0, b.InFlight(), "slot must not leak when catch panics")
+}
+
+func TestBulkhead_ReleasedBeforeCatch(t *testing.T) {
+	b := NewBulkhead(1, 0, 0)
+	var inFlight int
+
+	_ = NewWithOptions(WithBulkhead(b)).
+		Try(func() error { return errors.New("boom") }).
+		Catch(func(error) { inFlight = b.InFlight() }).
+		Do()
+
+	assert.Equal(t, 0, inFlight)
+}
+
+func TestBulkhead_RejectionNotCountedByBreaker(t *testing.T) {
+	b := NewBulkhead(1, 0, 0)
+	cb := NewBreaker("bulkhead", BreakerConfig{MinRequests: 1})
+	assert.NoError(t, b.Acquire(context.Background()))
+	defer b.Release()
+
+	err := NewWithOptions(WithBulkhead(b), WithCircuitBreaker(cb)).Try(func() error { return nil }).Do()
+	assert.ErrorIs(t, err, ErrBulkheadFull)
+	assert.Equal(t, BreakerClosed, cb.State())
+}
+
+func TestNewBulkhead_Normalizes(t *testing.T) {
+	b := NewBulkhead(0, -1, 0)
+	assert.Equal(t, 1, cap(b.slots))
+	assert.Equal(t, int64(0), b.maxQueue)
+}
+
+func TestWithBulkhead_EnforceDeadlineKeepsSlot(t *testing.T) {
+	b := NewBulkhead(1, 0, 0)
+	release := make(chan struct{})
+	var running, peak atomic.Int32
+	body := func(context.Context) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		return nil
+	}
+
+	err := NewWithOptions(WithBulkhead(b), WithTimeout(10*time.Millisecond), WithEnforceDeadline()).TryCtx(body).Do()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, b.InFlight(), "the abandoned body still holds its slot")
+
+	err = NewWithOptions(WithBulkhead(b), WithTimeout(10*time.Millisecond), WithEnforceDeadline()).TryCtx(body).Do()
+	assert.ErrorIs(t, err, ErrBulkheadFull, "no second body may start while the abandoned one runs")
+
+	close(release)
+	waitFor(t, func() bool { return b.InFlight() == 0 })
+	assert.Equal(t, int32(1), peak.Load())
+
+	err = NewWithOptions(WithBulkhead(b), WithTimeout(time.Second), WithEnforceDeadline()).TryCtx(body).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, b.InFlight(), "a body finishing before the deadline releases its slot once")
+}
@@ -0,0 +1,96 @@
+package gotrycatch
+
+import (
+	"errors"
+)
+
+// catchHandler 封装两种形式的 catch 处理函数，二者最多设置其一
+type catchHandler struct {
+	fn    func(error)       // 只处理错误，Do 仍返回原错误
+	fnErr func(error) error // 返回值决定 Do 最终返回的错误
+}
+
+// valid 判断是否设置了处理函数
+func (h catchHandler) valid() bool {
+	return h.fn != nil || h.fnErr != nil
+}
+
+// call 在隔离环境中执行处理函数，捕获处理函数内部的 panic 并返回。
+// result 为处理后的错误；panicVal 非 nil 表示处理函数发生了 panic。
+func (h catchHandler) call(err error) (result error, panicVal any) {
+	defer func() { panicVal = recover() }()
+	if h.fnErr != nil {
+		return h.fnErr(err), nil
+	}
+	h.fn(err)
+	return err, nil
+}
+
+// catchClause 描述一个带匹配条件的 catch 子句
+type catchClause struct {
+	match   func(error) bool // 判断错误是否由该子句处理
+	handler catchHandler     // 匹配成功后执行的处理函数
+}
+
+// CatchIs 添加一个 catch 子句，当错误链中包含 target（errors.Is）时执行 fn
+// 多个子句按注册顺序匹配，只执行第一个匹配的子句
+func (tc *TryCatchBlock) CatchIs(target error, fn func(error)) *TryCatchBlock {
+	tc.clauses = append(tc.clauses, catchClause{
+		match:   func(err error) bool { return errors.Is(err, target) },
+		handler: catchHandler{fn: fn},
+	})
+	return tc
+}
+
+// CatchIsErr 类似 CatchIs，但 fn 的返回值会替换 Do 返回的错误（语义同 CatchErr）
+func (tc *TryCatchBlock) CatchIsErr(target error, fn func(error) error) *TryCatchBlock {
+	tc.clauses = append(tc.clauses, catchClause{
+		match:   func(err error) bool { return errors.Is(err, target) },
+		handler: catchHandler{fnErr: fn},
+	})
+	return tc
+}
+
+// CatchAs 添加一个 catch 子句，当错误链中存在类型为 E 的错误（errors.As）时执行 fn
+// Go 的方法不支持类型参数，因此 CatchAs 以包级函数的形式提供
+func CatchAs[E error](tc *TryCatchBlock, fn func(E)) *TryCatchBlock {
+	tc.clauses = append(tc.clauses, catchClause{
+		match: matchAs[E],
+		handler: catchHandler{fn: func(err error) {
+			var target E
+			errors.As(err, &target)
+			fn(target)
+		}},
+	})
+	return tc
+}
+
+// CatchAsErr 类似 CatchAs，但 fn 的返回值会替换 Do 返回的错误（语义同 CatchErr）
+func CatchAsErr[E error](tc *TryCatchBlock, fn func(E) error) *TryCatchBlock {
+	tc.clauses = append(tc.clauses, catchClause{
+		match: matchAs[E],
+		handler: catchHandler{fnErr: func(err error) error {
+			var target E
+			errors.As(err, &target)
+			return fn(target)
+		}},
+	})
+	return tc
+}
+
+// matchAs 判断错误链中是否存在类型为 E 的错误
+func matchAs[E error](err error) bool {
+	var target E
+	return errors.As(err, &target)
+}
+
+// handler 返回处理 err 的 catch 函数
+// 按注册顺序返回第一个匹配的子句；没有子句匹配时返回 Catch / CatchErr 设置的兜底函数（可能为空）
+func (tc *TryCatchBlock) handler(err error) catchHandler {
+	for i := range tc.clauses {
+		if tc.clauses[i].match(err) {
+			return tc.clauses[i].handler
+		}
+	}
+	return catchHandler{fn: tc.catch, fnErr: tc.catchErr}
+}
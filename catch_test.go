@@ -0,0 +1,293 @@
+package gotrycatch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errNotFound = errors.New("not found")
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid field: " + e.field
+}
+
+func TestCatchIs_Match(t *testing.T) {
+	var matched, fallback bool
+
+	err := New().
+		Try(func() error {
+			return fmt.Errorf("load user: %w", errNotFound)
+		}).
+		CatchIs(errNotFound, func(err error) {
+			matched = true
+		}).
+		Catch(func(err error) {
+			fallback = true
+		}).
+		Do()
+
+	assert.ErrorIs(t, err, errNotFound)
+	assert.True(t, matched, "CatchIs clause should handle matching error")
+	assert.False(t, fallback, "fallback catch should not run when a clause matches")
+}
+
+func TestCatchAs_Match(t *testing.T) {
+	var field string
+
+	err := CatchAs(New().
+		Try(func() error {
+			return fmt.Errorf("wrapped: %w", &validationError{field: "email"})
+		}),
+		func(err *validationError) {
+			field = err.field
+		}).
+		Do()
+
+	assert.Error(t, err)
+	assert.Equal(t, "email", field)
+}
+
+func TestCatchClauses_FirstMatchWins(t *testing.T) {
+	var order []string
+
+	tc := New().
+		Try(func() error {
+			return fmt.Errorf("%w: %w", errNotFound, &validationError{field: "id"})
+		}).
+		CatchIs(errNotFound, func(err error) {
+			order = append(order, "is")
+		})
+	CatchAs(tc, func(err *validationError) {
+		order = append(order, "as")
+	})
+	tc.Do()
+
+	assert.Equal(t, []string{"is"}, order, "only the first matching clause should run")
+}
+
+func TestCatchClauses_FallbackWhenNoMatch(t *testing.T) {
+	otherErr := errors.New("other")
+	var clauseCalled bool
+	var caughtErr error
+
+	tc := New().
+		Try(func() error {
+			return otherErr
+		}).
+		CatchIs(errNotFound, func(err error) {
+			clauseCalled = true
+		}).
+		Catch(func(err error) {
+			caughtErr = err
+		})
+	err := tc.Do()
+
+	assert.False(t, clauseCalled)
+	assert.Equal(t, otherErr, caughtErr, "fallback catch should receive unmatched error")
+	assert.Equal(t, otherErr, err)
+}
+
+func TestCatchClauses_UnmatchedWithoutFallback(t *testing.T) {
+	otherErr := errors.New("other")
+	onCatchCalled := false
+
+	err := New().
+		ApplyOptions(WithHooks(Hooks{
+			OnCatch: func(error) { onCatchCalled = true },
+		})).
+		Try(func() error {
+			return otherErr
+		}).
+		CatchIs(errNotFound, func(err error) {
+			t.Error("clause should not be called for unmatched error")
+		}).
+		Do()
+
+	assert.Equal(t, otherErr, err, "unmatched error should be returned unchanged")
+	assert.False(t, onCatchCalled, "OnCatch should not be called when no handler runs")
+}
+
+func TestCatchAs_PanicError(t *testing.T) {
+	var panicValue any
+
+	CatchAs(New().
+		Try(func() error {
+			panic("boom")
+		}),
+		func(err *PanicError) {
+			panicValue = err.Value
+		}).
+		Do()
+
+	assert.Equal(t, "boom", panicValue)
+}
+
+func TestCatchIs_PanicWithError(t *testing.T) {
+	matched := false
+
+	err := New().
+		Try(func() error {
+			panic(errNotFound)
+		}).
+		CatchIs(errNotFound, func(err error) {
+			matched = true
+		}).
+		Do()
+
+	assert.True(t, matched, "CatchIs should see through *PanicError")
+	assert.ErrorIs(t, err, errNotFound)
+}
+
+func TestCatchClauses_PanicInClause(t *testing.T) {
+	finallyCalled := false
+
+	tc := New().
+		Try(func() error {
+			return errNotFound
+		}).
+		CatchIs(errNotFound, func(err error) {
+			panic("panic in clause")
+		}).
+		Finally(func() {
+			finallyCalled = true
+		})
+
+	assert.Panics(t, func() { tc.Do() })
+	assert.True(t, finallyCalled, "finally should run even when a clause panics")
+}
+
+func TestCatchClauses_Reset(t *testing.T) {
+	tc := New().CatchIs(errNotFound, func(error) {})
+	CatchAs(tc, func(*validationError) {})
+	assert.Len(t, tc.clauses, 2)
+
+	tc.Reset()
+
+	assert.Empty(t, tc.clauses, "clauses should be cleared after Reset")
+	err := tc.Try(func() error { return errNotFound }).Do()
+	assert.Equal(t, errNotFound, err)
+}
+
+func TestCatchErr_Swallow(t *testing.T) {
+	err := New().
+		Try(func() error {
+			return errNotFound
+		}).
+		CatchErr(func(err error) error {
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err, "returning nil from CatchErr should mark the error as handled")
+}
+
+func TestCatchErr_Replace(t *testing.T) {
+	domainErr := errors.New("domain error")
+
+	err := New().
+		Try(func() error {
+			panic("boom")
+		}).
+		CatchErr(func(err error) error {
+			return fmt.Errorf("%w: %v", domainErr, err)
+		}).
+		Do()
+
+	assert.ErrorIs(t, err, domainErr, "CatchErr should replace the error returned by Do")
+	assert.Equal(t, "domain error: boom", err.Error())
+}
+
+func TestCatchErr_Rethrow(t *testing.T) {
+	err := New().
+		Try(func() error {
+			return errNotFound
+		}).
+		CatchErr(func(err error) error {
+			return err
+		}).
+		Do()
+
+	assert.Equal(t, errNotFound, err, "returning the same error should rethrow it")
+}
+
+func TestCatchErr_LastSetterWins(t *testing.T) {
+	tc := New().
+		Try(func() error {
+			return errNotFound
+		}).
+		CatchErr(func(err error) error { return nil }).
+		Catch(func(err error) {})
+
+	assert.Nil(t, tc.catchErr, "Catch should replace CatchErr")
+	assert.Equal(t, errNotFound, tc.Do())
+
+	tc.CatchErr(func(err error) error { return nil })
+	assert.Nil(t, tc.catch, "CatchErr should replace Catch")
+	assert.NoError(t, tc.Do())
+}
+
+func TestCatchErr_PanicPropagates(t *testing.T) {
+	finallyCalled := false
+	tc := New().
+		Try(func() error {
+			return errNotFound
+		}).
+		CatchErr(func(err error) error {
+			panic("panic in catch")
+		}).
+		Finally(func() {
+			finallyCalled = true
+		})
+
+	assert.Panics(t, func() { tc.Do() })
+	assert.True(t, finallyCalled)
+}
+
+func TestCatchIsErr_Translate(t *testing.T) {
+	err := New().
+		Try(func() error {
+			return fmt.Errorf("query: %w", errNotFound)
+		}).
+		CatchIsErr(errNotFound, func(err error) error {
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+}
+
+func TestCatchAsErr_Translate(t *testing.T) {
+	domainErr := errors.New("bad request")
+
+	err := CatchAsErr(New().
+		Try(func() error {
+			return &validationError{field: "name"}
+		}),
+		func(err *validationError) error {
+			return fmt.Errorf("%w: %s", domainErr, err.field)
+		}).
+		Do()
+
+	assert.ErrorIs(t, err, domainErr)
+	assert.Equal(t, "bad request: name", err.Error())
+}
+
+func TestCatchAs_KeepsOriginalError(t *testing.T) {
+	original := fmt.Errorf("wrapped: %w", &validationError{field: "age"})
+
+	err := CatchAs(New().
+		Try(func() error {
+			return original
+		}),
+		func(err *validationError) {}).
+		Do()
+
+	assert.Equal(t, original, err, "CatchAs should not change the error returned by Do")
+}
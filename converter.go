@@ -0,0 +1,91 @@
+package gotrycatch
+
+import (
+	"strconv"
+)
+
+// PanicConverter 将 recover() 得到的值转换为 error
+// stack 为 panic 发生时的调用栈文本。返回 nil 表示不处理，由默认的 *PanicError 兜底
+type PanicConverter interface {
+	Convert(recovered any, stack []byte) error
+}
+
+// PanicConverterFunc 是函数形式的 PanicConverter
+type PanicConverterFunc func(recovered any, stack []byte) error
+
+// Convert 调用 f 本身
+func (f PanicConverterFunc) Convert(recovered any, stack []byte) error {
+	return f(recovered, stack)
+}
+
+// SetPanicConverter 设置进程级的 panic 转换器，作用于所有 TryCatchBlock.Do 与泛型函数
+// 传入 nil 恢复默认行为（返回 *PanicError）。可并发调用
+func SetPanicConverter(converter PanicConverter) {
+	updateDefaults(func(d *defaults) {
+		d.converter = converter
+	})
+}
+
+// WithPanicConverter 为块设置 panic 转换器，优先于 SetPanicConverter 设置的进程级转换器
+func WithPanicConverter(converter PanicConverter) Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().converter = converter
+	}
+}
+
+// panicConverter 返回进程级的 panic 转换器，未设置时返回 nil
+func (d *defaults) panicConverter() PanicConverter {
+	if d == nil {
+		return nil
+	}
+	return d.converter
+}
+
+// panicConverter 返回块使用的 panic 转换器，块未设置时使用进程级的转换器
+func (tc *TryCatchBlock) panicConverter(d *defaults) PanicConverter {
+	if c := tc.options().converter; c != nil {
+		return c
+	}
+	return d.panicConverter()
+}
+
+// convertPanic 将 recover() 得到的值转换为最终返回给调用方的 error
+// 先构造 *PanicError（并通知 panic 处理函数），再交给 converter 转换；
+// converter 为 nil、返回 nil 或自身发生 panic 时，返回 *PanicError；由 Throw 抛出的错误原样返回
+func convertPanic(r any, name string, skip int, d *defaults, converter PanicConverter) error {
+	if err, ok := thrown(r); ok {
+		return err
+	}
+	pe := newPanicError(r, name, skip+1, d)
+	if converter == nil {
+		return pe
+	}
+	if err := safeConvert(converter, pe); err != nil {
+		return err
+	}
+	return pe
+}
+
+// safeConvert 在隔离环境中调用 converter，converter 发生 panic 时返回 nil
+func safeConvert(converter PanicConverter, pe *PanicError) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = nil
+		}
+	}()
+	return converter.Convert(pe.Value, formatStack(pe))
+}
+
+// formatStack 将 *PanicError 的调用栈格式化为与 runtime/debug.Stack 类似的文本
+func formatStack(pe *PanicError) []byte {
+	var buf []byte
+	for _, frame := range pe.Stack() {
+		buf = append(buf, frame.Function...)
+		buf = append(buf, "\n\t"...)
+		buf = append(buf, frame.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
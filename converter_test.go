@@ -0,0 +1,184 @@
+package gotrycatch
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errBug = errors.New("programming bug")
+
+// runtimeErrorConverter 将 runtime.Error 映射为 errBug
+var runtimeErrorConverter = PanicConverterFunc(func(recovered any, stack []byte) error {
+	if re, ok := recovered.(runtime.Error); ok {
+		return fmt.Errorf("%w: %v", errBug, re)
+	}
+	return nil
+})
+
+func TestWithPanicConverter_Maps(t *testing.T) {
+	var caughtErr error
+
+	err := NewWithOptions(WithPanicConverter(runtimeErrorConverter)).
+		Try(func() error {
+			var m map[string]int
+			m["x"] = 1
+			return nil
+		}).
+		Catch(func(err error) {
+			caughtErr = err
+		}).
+		Do()
+
+	assert.ErrorIs(t, err, errBug, "runtime.Error should be mapped to the domain error")
+	assert.Equal(t, err, caughtErr, "catch should receive the converted error")
+}
+
+func TestWithPanicConverter_NilFallsBack(t *testing.T) {
+	err := NewWithOptions(WithPanicConverter(runtimeErrorConverter)).
+		Try(func() error {
+			panic("business panic")
+		}).
+		Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "nil from converter should fall back to *PanicError")
+}
+
+func TestWithPanicConverter_ReceivesStack(t *testing.T) {
+	var gotStack []byte
+	var gotValue any
+
+	NewWithOptions(WithPanicConverter(PanicConverterFunc(func(recovered any, stack []byte) error {
+		gotValue, gotStack = recovered, stack
+		return errors.New("converted")
+	}))).
+		Try(func() error {
+			panic("with stack")
+		}).
+		Do()
+
+	assert.Equal(t, "with stack", gotValue)
+	assert.True(t, strings.Contains(string(gotStack), "TestWithPanicConverter_ReceivesStack"), "stack should include the panicking function")
+}
+
+func TestWithPanicConverter_ConverterPanics(t *testing.T) {
+	var err error
+
+	assert.NotPanics(t, func() {
+		err = NewWithOptions(WithPanicConverter(PanicConverterFunc(func(any, []byte) error {
+			panic("converter panic")
+		}))).
+			Try(func() error {
+				panic("original")
+			}).
+			Do()
+	})
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "original", panicErr.Value)
+}
+
+func TestWithPanicConverter_FinallyPanic(t *testing.T) {
+	converted := errors.New("converted finally panic")
+
+	err := NewWithOptions(WithPanicConverter(PanicConverterFunc(func(any, []byte) error {
+		return converted
+	}))).
+		Try(func() error { return nil }).
+		Finally(func() { panic("finally") }).
+		Do()
+
+	assert.Equal(t, converted, err)
+}
+
+func TestSetPanicConverter_Global(t *testing.T) {
+	resetDefaults(t)
+	SetPanicConverter(runtimeErrorConverter)
+
+	err := New().
+		Try(func() error {
+			var s []int
+			_ = s[1]
+			return nil
+		}).
+		Do()
+	assert.ErrorIs(t, err, errBug)
+
+	_, err = TryWithResult(func() (int, error) {
+		var p *struct{ v int }
+		return p.v, nil
+	})
+	assert.ErrorIs(t, err, errBug, "global converter should apply to generic helpers")
+}
+
+func TestSetPanicConverter_BlockOverridesGlobal(t *testing.T) {
+	resetDefaults(t)
+	globalErr := errors.New("global")
+	blockErr := errors.New("block")
+	SetPanicConverter(PanicConverterFunc(func(any, []byte) error { return globalErr }))
+
+	err := NewWithOptions(WithPanicConverter(PanicConverterFunc(func(any, []byte) error { return blockErr }))).
+		Try(func() error { panic("boom") }).
+		Do()
+	assert.Equal(t, blockErr, err)
+
+	err = NewWithOptions(WithoutDefaults()).
+		Try(func() error { panic("boom") }).
+		Do()
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "WithoutDefaults should skip the global converter")
+}
+
+func TestSetPanicConverter_HandlersStillNotified(t *testing.T) {
+	resetDefaults(t)
+	var reported *PanicError
+	RegisterPanicHandler(func(pe *PanicError) { reported = pe })
+	SetPanicConverter(PanicConverterFunc(func(any, []byte) error { return errBug }))
+
+	err := New().Try(func() error { panic("boom") }).Do()
+
+	assert.Equal(t, errBug, err)
+	assert.NotNil(t, reported, "panic handlers should receive the *PanicError even when converted")
+	assert.Equal(t, "boom", reported.Value)
+}
+
+func TestSetPanicConverter_Reset(t *testing.T) {
+	resetDefaults(t)
+	SetPanicConverter(runtimeErrorConverter)
+	SetPanicConverter(nil)
+
+	err := New().Try(func() error { panic("boom") }).Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+}
+
+func TestWithPanicConverter_RetryReportsOnce(t *testing.T) {
+	var calls int
+
+	NewWithOptions(
+		WithRetry(RetryPolicy{MaxAttempts: 3}),
+		WithPanicConverter(PanicConverterFunc(func(any, []byte) error {
+			calls++
+			return errBug
+		})),
+	).
+		Try(func() error { panic("boom") }).
+		Do()
+
+	assert.Equal(t, 1, calls, "converter should run once when the panic leaves Do")
+}
+
+func TestWithPanicConverter_ResetClears(t *testing.T) {
+	tc := NewWithOptions(WithPanicConverter(runtimeErrorConverter))
+
+	tc.Reset()
+
+	assert.Nil(t, tc.options().converter, "converter should be cleared after Reset")
+}
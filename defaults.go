@@ -0,0 +1,143 @@
+package gotrycatch
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// defaults 保存进程级的默认钩子与 panic 处理函数
+// 采用写时复制：写入时在锁内生成新的快照，读取时只需一次原子加载
+type defaults struct {
+	hooks     Hooks               // 默认钩子，在块自身的钩子之前调用
+	handlers  []func(*PanicError) // 已注册的 panic 处理函数
+	converter PanicConverter      // 默认的 panic 转换器
+}
+
+var (
+	defaultsMu  sync.Mutex               // 串行化写入
+	defaultsPtr atomic.Pointer[defaults] // 当前快照，nil 表示未配置
+)
+
+// loadDefaults 返回当前的默认配置快照，未配置时返回 nil
+func loadDefaults() *defaults {
+	return defaultsPtr.Load()
+}
+
+// updateDefaults 在锁内基于当前快照生成并发布新的快照
+func updateDefaults(update func(d *defaults)) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+
+	next := &defaults{}
+	if cur := defaultsPtr.Load(); cur != nil {
+		next.hooks = cur.hooks
+		next.converter = cur.converter
+		next.handlers = cur.handlers[:len(cur.handlers):len(cur.handlers)]
+	}
+	update(next)
+	defaultsPtr.Store(next)
+}
+
+// SetDefaultHooks 设置进程级的默认钩子，作用于所有 TryCatchBlock.Do 以及 TryWithResult、TryCatchR 等泛型函数
+// 默认钩子在块自身的钩子之前调用；可并发调用，再次调用会替换之前设置的默认钩子
+func SetDefaultHooks(hooks Hooks) {
+	updateDefaults(func(d *defaults) {
+		d.hooks = hooks
+	})
+}
+
+// RegisterPanicHandler 注册进程级的 panic 处理函数，每个被恢复的 panic 都会以 *PanicError 的形式传给它
+// 适用于将所有 panic 上报到崩溃收集系统；处理函数内部的 panic 会被忽略。可并发调用
+func RegisterPanicHandler(handler func(*PanicError)) {
+	if handler == nil {
+		return
+	}
+	updateDefaults(func(d *defaults) {
+		d.handlers = append(d.handlers, handler)
+	})
+}
+
+// WithoutDefaults 使块不使用 SetDefaultHooks、RegisterPanicHandler 与 SetPanicConverter 设置的进程级配置
+func WithoutDefaults() Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().noDefaults = true
+	}
+}
+
+// defaults 返回块使用的进程级配置，块选择退出或未配置时返回 nil
+func (tc *TryCatchBlock) defaults() *defaults {
+	if tc.options().noDefaults {
+		return nil
+	}
+	return loadDefaults()
+}
+
+// notifyPanic 将 *PanicError 依次传给已注册的 panic 处理函数
+func (d *defaults) notifyPanic(pe *PanicError) {
+	if d == nil {
+		return
+	}
+	for _, handler := range d.handlers {
+		func() {
+			defer func() { _ = recover() }()
+			handler(pe)
+		}()
+	}
+}
+
+// onTryStart 调用默认的 OnTryStart 钩子
+func (d *defaults) onTryStart() {
+	if d != nil && d.hooks.OnTryStart != nil {
+		d.hooks.OnTryStart()
+	}
+}
+
+// onTryEnd 调用默认的 OnTryEnd 钩子
+func (d *defaults) onTryEnd(err error) {
+	if d != nil && d.hooks.OnTryEnd != nil {
+		d.hooks.OnTryEnd(err)
+	}
+}
+
+// onCatch 调用默认的 OnCatch 钩子
+func (d *defaults) onCatch(err error) {
+	if d != nil && d.hooks.OnCatch != nil {
+		d.hooks.OnCatch(err)
+	}
+}
+
+// onFinally 在隔离环境中调用默认的 OnFinally 钩子，返回钩子中 panic 转换得到的错误
+func (d *defaults) onFinally(name string, converter PanicConverter) error {
+	if d == nil || d.hooks.OnFinally == nil {
+		return nil
+	}
+	return finallyGuard(d.hooks.OnFinally, nil, name, d, converter)
+}
+
+// onAbandon 调用默认的 OnAbandon 钩子
+func (d *defaults) onAbandon(err error) {
+	if d != nil && d.hooks.OnAbandon != nil {
+		d.hooks.OnAbandon(err)
+	}
+}
+
+// onCatchPanic 调用默认的 OnCatchPanic 钩子
+func (d *defaults) onCatchPanic(recovered any) {
+	if d != nil && d.hooks.OnCatchPanic != nil {
+		d.hooks.OnCatchPanic(recovered)
+	}
+}
+
+// onCancel 调用默认的 OnCancel 钩子
+func (d *defaults) onCancel(err error) {
+	if d != nil && d.hooks.OnCancel != nil {
+		d.hooks.OnCancel(err)
+	}
+}
+
+// onRetry 调用默认的 OnRetry 钩子
+func (d *defaults) onRetry(attempt int, err error) {
+	if d != nil && d.hooks.OnRetry != nil {
+		d.hooks.OnRetry(attempt, err)
+	}
+}
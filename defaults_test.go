@@ -0,0 +1,220 @@
+package gotrycatch
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// resetDefaults 清理进程级配置，避免影响其他测试
+func resetDefaults(t *testing.T) {
+	t.Helper()
+	defaultsPtr.Store(nil)
+	t.Cleanup(func() { defaultsPtr.Store(nil) })
+}
+
+func TestSetDefaultHooks_AppliesToDo(t *testing.T) {
+	resetDefaults(t)
+	var order []string
+
+	SetDefaultHooks(Hooks{
+		OnTryStart: func() { order = append(order, "default-start") },
+		OnCatch:    func(error) { order = append(order, "default-catch") },
+		OnFinally:  func() { order = append(order, "default-finally") },
+	})
+
+	New().
+		ApplyOptions(WithHooks(Hooks{
+			OnTryStart: func() { order = append(order, "block-start") },
+			OnFinally:  func() { order = append(order, "block-finally") },
+		})).
+		Try(func() error {
+			return errors.New("error")
+		}).
+		Catch(func(error) {}).
+		Do()
+
+	assert.Equal(t, []string{
+		"default-start",
+		"block-start",
+		"default-catch",
+		"default-finally",
+		"block-finally",
+	}, order, "default hooks should run before block hooks")
+}
+
+func TestSetDefaultHooks_Replace(t *testing.T) {
+	resetDefaults(t)
+	var first, second int
+
+	SetDefaultHooks(Hooks{OnTryStart: func() { first++ }})
+	SetDefaultHooks(Hooks{OnTryStart: func() { second++ }})
+
+	New().Try(func() error { return nil }).Do()
+
+	assert.Equal(t, 0, first, "previous default hooks should be replaced")
+	assert.Equal(t, 1, second)
+}
+
+func TestRegisterPanicHandler_Do(t *testing.T) {
+	resetDefaults(t)
+	var reported []*PanicError
+
+	RegisterPanicHandler(func(pe *PanicError) { reported = append(reported, pe) })
+
+	err := New().
+		ApplyOptions(WithName("reported")).
+		Try(func() error {
+			panic("boom")
+		}).
+		Do()
+
+	assert.Len(t, reported, 1)
+	assert.Same(t, err, reported[0], "handler should receive the returned *PanicError")
+	assert.Equal(t, "reported", reported[0].Name)
+}
+
+func TestRegisterPanicHandler_ReportedOnce(t *testing.T) {
+	resetDefaults(t)
+	var count int
+
+	RegisterPanicHandler(func(*PanicError) { count++ })
+
+	New().
+		ApplyOptions(WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() error {
+			panic("boom")
+		}).
+		Do()
+
+	assert.Equal(t, 1, count, "a panic re-thrown inside Do should be reported only once")
+}
+
+func TestRegisterPanicHandler_Generics(t *testing.T) {
+	resetDefaults(t)
+	var count int
+
+	RegisterPanicHandler(func(*PanicError) { count++ })
+
+	TryWithResult(func() (int, error) { panic("a") })
+	TryWithResultAndFinally(func() (int, error) { panic("b") }, nil)
+	TryCatchR(func() (int, error) { panic("c") }, nil, nil)
+	TryCatchRErr(func() (int, error) { panic("d") }, nil, nil)
+
+	assert.Equal(t, 4, count)
+}
+
+func TestRegisterPanicHandler_FinallyPanic(t *testing.T) {
+	resetDefaults(t)
+	var values []any
+
+	RegisterPanicHandler(func(pe *PanicError) { values = append(values, pe.Value) })
+
+	New().
+		Try(func() error { return nil }).
+		Finally(func() { panic("finally panic") }).
+		Do()
+
+	assert.Equal(t, []any{"finally panic"}, values)
+}
+
+func TestRegisterPanicHandler_HandlerPanicIgnored(t *testing.T) {
+	resetDefaults(t)
+	secondCalled := false
+
+	RegisterPanicHandler(func(*PanicError) { panic("handler panic") })
+	RegisterPanicHandler(func(*PanicError) { secondCalled = true })
+
+	var err error
+	assert.NotPanics(t, func() {
+		err = New().Try(func() error { panic("boom") }).Do()
+	})
+	assert.Equal(t, "boom", err.Error())
+	assert.True(t, secondCalled, "a panicking handler should not prevent others from running")
+}
+
+func TestSetDefaultHooks_Generics(t *testing.T) {
+	resetDefaults(t)
+	var starts, ends, catches, finallies int
+
+	SetDefaultHooks(Hooks{
+		OnTryStart: func() { starts++ },
+		OnTryEnd:   func(error) { ends++ },
+		OnCatch:    func(error) { catches++ },
+		OnFinally:  func() { finallies++ },
+	})
+
+	TryWithResult(func() (int, error) { return 1, nil })
+	TryCatchR(func() (int, error) { return 0, errors.New("error") }, func(error) {}, nil)
+	TryCatchRErr(func() (int, error) { panic("boom") }, nil, nil)
+
+	assert.Equal(t, 3, starts)
+	assert.Equal(t, 2, ends, "OnTryEnd should not run when fn panics")
+	assert.Equal(t, 2, catches)
+	assert.Equal(t, 2, finallies)
+}
+
+func TestSetDefaultHooks_CatchPanicGenerics(t *testing.T) {
+	resetDefaults(t)
+	var recovered []any
+
+	SetDefaultHooks(Hooks{OnCatchPanic: func(r any) { recovered = append(recovered, r) }})
+
+	assert.Panics(t, func() {
+		_, _ = TryCatchR(func() (int, error) { return 0, errors.New("error") }, func(error) { panic("catch") }, nil)
+	})
+	assert.Panics(t, func() {
+		_, _ = TryCatchRErr(func() (int, error) { panic("boom") }, func(error) (int, error) { panic("catch err") }, nil)
+	})
+
+	assert.Equal(t, []any{"catch", "catch err"}, recovered)
+}
+
+func TestWithoutDefaults(t *testing.T) {
+	resetDefaults(t)
+	hookCalled, handlerCalled := false, false
+
+	SetDefaultHooks(Hooks{OnTryStart: func() { hookCalled = true }})
+	RegisterPanicHandler(func(*PanicError) { handlerCalled = true })
+
+	err := NewWithOptions(WithoutDefaults()).
+		Try(func() error { panic("boom") }).
+		Do()
+
+	assert.Error(t, err)
+	assert.False(t, hookCalled, "opted-out block should not run default hooks")
+	assert.False(t, handlerCalled, "opted-out block should not notify panic handlers")
+}
+
+func TestDefaults_ConcurrentAccess(t *testing.T) {
+	resetDefaults(t)
+	var wg sync.WaitGroup
+	var reported int32
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterPanicHandler(func(*PanicError) { atomic.AddInt32(&reported, 1) })
+			SetDefaultHooks(Hooks{OnTryStart: func() {}})
+		}()
+		go func() {
+			defer wg.Done()
+			New().Try(func() error { panic("concurrent") }).Do()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, loadDefaults().handlers, 10)
+}
+
+func TestWithoutDefaults_Reset(t *testing.T) {
+	tc := NewWithOptions(WithoutDefaults())
+
+	tc.Reset()
+
+	assert.False(t, tc.options().noDefaults, "noDefaults should be cleared after Reset")
+}
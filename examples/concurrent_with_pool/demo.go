@@ -10,9 +10,9 @@ import (
 func main() {
 	// Number of goroutines to create
 	const goroutineCount = 100
-	// WaitGroup to synchronize all goroutines
-	var waitGroup sync.WaitGroup
-	waitGroup.Add(goroutineCount)
+	// Group to run and wait for all goroutines
+	// A zero Group never cancels, so every goroutine runs even after a failure
+	var group gtc.Group
 
 	// Create a sync.Pool to reuse TryCatchBlock instances
 	// This helps reduce memory allocations in concurrent scenarios
@@ -24,14 +24,13 @@ func main() {
 
 	// Launch goroutines
 	for i := 0; i < goroutineCount; i++ {
-		go func(routineID int) {
-			// Ensure WaitGroup is decremented when goroutine completes
-			defer waitGroup.Done()
-
+		routineID := i
+		group.Go(func() error {
 			// Get a TryCatchBlock instance from the pool
 			tryCatch := pool.Get().(*gtc.TryCatchBlock)
 
 			// Execute the try-catch-finally block
+			// Errors are handled in catch, so the goroutine reports success to the group
 			tryCatch.Try(func() error {
 				// Simulate error for even-numbered routines
 				if routineID%2 == 0 {
@@ -49,6 +48,10 @@ func main() {
 			// Reset the TryCatchBlock before returning it to the pool
 			tryCatch.Reset()
 			pool.Put(tryCatch)
-		}(i)
+			return nil
+		})
 	}
+
+	// Wait for all goroutines to complete
+	_ = group.Wait()
 }
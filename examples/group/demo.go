@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+)
+
+func main() {
+	// Number of tasks to run
+	const taskCount = 100
+
+	// Create a Group whose context is cancelled on the first failure
+	// Every task runs under TryCatchBlock semantics, so panics become errors
+	group, ctx := gtc.NewGroup(context.Background(),
+		gtc.WithGroupJoinErrors(),
+		gtc.WithGroupBlockOptions(gtc.WithName("worker")),
+	)
+
+	// Limit the number of tasks running at the same time
+	group.SetLimit(10)
+
+	// Launch tasks
+	for i := 0; i < taskCount; i++ {
+		taskID := i
+		group.Go(func() error {
+			// Stop early once another task has failed
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			// Simulate a panic for one task and an error for another
+			switch taskID {
+			case 13:
+				panic(fmt.Sprintf("task %d crashed", taskID))
+			case 42:
+				return fmt.Errorf("error from task %d", taskID)
+			}
+			return nil
+		})
+	}
+
+	// Wait for all tasks and print every failure
+	if err := group.Wait(); err != nil {
+		fmt.Printf("Group finished with errors:\n%v\n", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package expvartc 通过标准库 expvar 发布 gotrycatch 块的执行指标，不引入第三方依赖
+//
+// 所有指标以块名称为键：
+//
+//	<prefix>.executions      Do 的执行次数
+//	<prefix>.errors          try 返回错误的次数
+//	<prefix>.panics          try 发生 panic 的次数
+//	<prefix>.catch_panics    catch 自身发生 panic 的次数
+//	<prefix>.cancellations   context 取消的次数
+//	<prefix>.duration        try 耗时直方图（秒）
+package expvartc
+
+import (
+	"expvar"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+)
+
+// DefaultBuckets 是耗时直方图默认的桶上界（秒），与 Prometheus 的默认桶一致
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// 确保 Metrics 实现了 gtc.Metrics
+var _ gtc.Metrics = (*Metrics)(nil)
+
+// config 保存 Metrics 的配置
+type config struct {
+	buckets []float64 // 耗时直方图的桶上界（秒），按升序排列
+}
+
+// Option 定义 Metrics 的配置选项
+type Option func(*config)
+
+// WithBuckets 指定耗时直方图的桶上界（秒），需按升序排列
+func WithBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// Metrics 将块的执行指标发布到 expvar，实现 gtc.Metrics，可以被多个块并发共享
+type Metrics struct {
+	executions    *expvar.Map
+	errors        *expvar.Map
+	panics        *expvar.Map
+	catchPanics   *expvar.Map
+	cancellations *expvar.Map
+	durations     *expvar.Map // 块名称 -> *histogram
+	buckets       []float64
+	mu            sync.Mutex // 保护直方图的创建
+}
+
+// New 创建 Metrics 并以 prefix 为前缀发布到 expvar
+// 与 expvar.NewMap 一致，同一 prefix 重复发布会 panic，因此每个进程中每个 prefix 只应调用一次
+func New(prefix string, opts ...Option) *Metrics {
+	cfg := config{buckets: DefaultBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Metrics{
+		executions:    expvar.NewMap(prefix + ".executions"),
+		errors:        expvar.NewMap(prefix + ".errors"),
+		panics:        expvar.NewMap(prefix + ".panics"),
+		catchPanics:   expvar.NewMap(prefix + ".catch_panics"),
+		cancellations: expvar.NewMap(prefix + ".cancellations"),
+		durations:     expvar.NewMap(prefix + ".duration"),
+		buckets:       cfg.buckets,
+	}
+}
+
+// IncExecutions 增加 Do 的执行次数
+func (m *Metrics) IncExecutions(name string) { m.executions.Add(name, 1) }
+
+// IncErrors 增加 try 返回错误的次数
+func (m *Metrics) IncErrors(name string) { m.errors.Add(name, 1) }
+
+// IncPanics 增加 try 发生 panic 的次数
+func (m *Metrics) IncPanics(name string) { m.panics.Add(name, 1) }
+
+// IncCatchPanics 增加 catch 自身发生 panic 的次数
+func (m *Metrics) IncCatchPanics(name string) { m.catchPanics.Add(name, 1) }
+
+// IncCancellations 增加 context 取消的次数
+func (m *Metrics) IncCancellations(name string) { m.cancellations.Add(name, 1) }
+
+// ObserveDuration 将 try 的耗时记录到块对应的直方图
+func (m *Metrics) ObserveDuration(name string, d time.Duration) {
+	m.histogram(name).observe(d)
+}
+
+// histogram 返回块对应的直方图，不存在时创建
+func (m *Metrics) histogram(name string) *histogram {
+	if h, ok := m.durations.Get(name).(*histogram); ok {
+		return h
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok := m.durations.Get(name).(*histogram); ok {
+		return h
+	}
+	h := newHistogram(m.buckets)
+	m.durations.Set(name, h)
+	return h
+}
+
+// histogram 是并发安全的累积直方图，实现 expvar.Var
+type histogram struct {
+	bounds []float64       // 桶上界（秒）
+	counts []atomic.Uint64 // 每个桶的非累积计数，最后一个为 +Inf 桶
+	count  atomic.Uint64   // 观测次数
+	sum    atomic.Int64    // 观测值之和（纳秒）
+}
+
+// newHistogram 创建指定桶上界的直方图
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+// observe 记录一次耗时
+func (h *histogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+	i := 0
+	for i < len(h.bounds) && seconds > h.bounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// String 以 JSON 返回直方图，buckets 为各上界的累积计数
+// 例如 {"count":3,"sum":0.12,"buckets":{"0.005":1,...,"+Inf":3}}
+func (h *histogram) String() string {
+	var b strings.Builder
+	b.WriteString(`{"count":`)
+	b.WriteString(strconv.FormatUint(h.count.Load(), 10))
+	b.WriteString(`,"sum":`)
+	b.WriteString(strconv.FormatFloat(time.Duration(h.sum.Load()).Seconds(), 'g', -1, 64))
+	b.WriteString(`,"buckets":{`)
+	var cumulative uint64
+	for i := range h.counts {
+		cumulative += h.counts[i].Load()
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('"')
+		if i < len(h.bounds) {
+			b.WriteString(strconv.FormatFloat(h.bounds[i], 'g', -1, 64))
+		} else {
+			b.WriteString("+Inf")
+		}
+		b.WriteString(`":`)
+		b.WriteString(strconv.FormatUint(cumulative, 10))
+	}
+	b.WriteString("}}")
+	return b.String()
+}
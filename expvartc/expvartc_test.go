@@ -0,0 +1,103 @@
+package expvartc
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"github.com/stretchr/testify/assert"
+)
+
+// prefixSeq 使每次运行发布的 expvar 名称各不相同，-count=N 时不会重复发布
+var prefixSeq atomic.Int64
+
+// uniquePrefix 返回本次运行独有的 expvar 前缀
+func uniquePrefix(t *testing.T) string {
+	return t.Name() + "_" + strconv.FormatInt(prefixSeq.Add(1), 10)
+}
+
+func counter(t *testing.T, name, key string) int64 {
+	t.Helper()
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !assert.True(t, ok, "%s should be published", name) {
+		return 0
+	}
+	v, ok := m.Get(key).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+func TestMetrics_Do(t *testing.T) {
+	prefix := uniquePrefix(t)
+	m := New(prefix)
+	opts := []gtc.Option{gtc.WithName("op"), gtc.WithMetrics(m)}
+
+	_ = gtc.NewWithOptions(opts...).Try(func() error { return nil }).Do()
+	_ = gtc.NewWithOptions(opts...).Try(func() error { return errors.New("boom") }).Do()
+	_ = gtc.NewWithOptions(opts...).Try(func() error { panic("boom") }).Do()
+	assert.Panics(t, func() {
+		_ = gtc.NewWithOptions(opts...).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	assert.Equal(t, int64(4), counter(t, prefix+".executions", "op"))
+	assert.Equal(t, int64(2), counter(t, prefix+".errors", "op"))
+	assert.Equal(t, int64(1), counter(t, prefix+".panics", "op"))
+	assert.Equal(t, int64(1), counter(t, prefix+".catch_panics", "op"))
+	assert.Equal(t, int64(0), counter(t, prefix+".cancellations", "op"))
+}
+
+func TestMetrics_Histogram(t *testing.T) {
+	prefix := uniquePrefix(t)
+	m := New(prefix, WithBuckets(0.01, 0.1))
+
+	m.ObserveDuration("op", 5*time.Millisecond)
+	m.ObserveDuration("op", 50*time.Millisecond)
+	m.ObserveDuration("op", time.Second)
+
+	var got struct {
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum"`
+		Buckets map[string]uint64 `json:"buckets"`
+	}
+	durations := expvar.Get(prefix + ".duration").(*expvar.Map)
+	assert.NoError(t, json.Unmarshal([]byte(durations.Get("op").String()), &got))
+	assert.Equal(t, uint64(3), got.Count)
+	assert.InDelta(t, 1.055, got.Sum, 1e-9)
+	assert.Equal(t, map[string]uint64{"0.01": 1, "0.1": 2, "+Inf": 3}, got.Buckets)
+	assert.True(t, json.Valid([]byte(durations.String())), "published map should be valid JSON")
+}
+
+func TestMetrics_Concurrent(t *testing.T) {
+	prefix := uniquePrefix(t)
+	m := New(prefix)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = gtc.NewWithOptions(gtc.WithName("op"), gtc.WithMetrics(m)).
+				Try(func() error { return nil }).
+				Do()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(50), counter(t, prefix+".executions", "op"))
+}
+
+func TestNew_DuplicatePrefixPanics(t *testing.T) {
+	prefix := uniquePrefix(t)
+	New(prefix)
+	assert.Panics(t, func() { New(prefix) })
+}
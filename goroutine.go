@@ -0,0 +1,66 @@
+package gotrycatch
+
+import (
+	"context"
+)
+
+// Handle 表示一个在独立 goroutine 中执行的 TryCatchBlock
+type Handle struct {
+	done chan struct{} // goroutine 结束时关闭
+	err  error         // Do 的返回值
+}
+
+// Wait 阻塞直到 goroutine 结束，返回 Do 的结果
+func (h *Handle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+// Done 返回一个在 goroutine 结束时关闭的 channel
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Go 在新的 goroutine 中执行 Do，返回用于等待结果的 Handle
+// catch 中的 panic 不会使进程崩溃，而是转换为错误（默认为 *PanicError）通过 Wait 返回；
+// 命中 WithRepanicPolicy 策略的 panic 除外。
+// 在 Wait 返回之前，调用方不应再修改或复用该块
+func (tc *TryCatchBlock) Go() *Handle {
+	h := &Handle{done: make(chan struct{})}
+	go func() {
+		defer close(h.done)
+		tc.guardDo(tc.Do, func(err error) { h.err = err })
+	}()
+	return h
+}
+
+// guardDo 执行 run（Do 或包装了 Do 的函数），并通过 report 报告结果，供在独立 goroutine 中执行块的函数使用
+// Do 向上传播的 panic（catch 中的 panic）被转换为错误报告，命中重新抛出策略的 panic 不应被吞掉，继续向上传播；
+// run 调用 runtime.Goexit 而没有返回时报告 ErrGoexit。除重新抛出的情况外，report 恰好被调用一次
+func (tc *TryCatchBlock) guardDo(run func() error, report func(error)) {
+	returned := false
+	defer func() {
+		if r := recover(); r != nil {
+			if tc.shouldRepanic(r) {
+				panic(r)
+			}
+			d := tc.defaults()
+			report(convertPanic(r, tc.name, 1, d, tc.panicConverter(d)))
+		} else if !returned {
+			report(ErrGoexit)
+		}
+	}()
+	err := run()
+	returned = true
+	report(err)
+}
+
+// Go 在新的 goroutine 中执行 fn，fn 中的 panic 会被捕获并转换为错误
+func Go(fn func() error, opts ...Option) *Handle {
+	return NewWithOptions(opts...).Try(fn).Go()
+}
+
+// GoCtx 类似 Go，但 fn 接收 ctx；ctx 会覆盖 opts 中通过 WithContext 设置的 context
+func GoCtx(ctx context.Context, fn func(context.Context) error, opts ...Option) *Handle {
+	return NewWithOptions(opts...).ApplyOptions(WithContext(ctx)).TryCtx(fn).Go()
+}
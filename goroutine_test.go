@@ -0,0 +1,151 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGo_Success(t *testing.T) {
+	h := Go(func() error {
+		return nil
+	})
+
+	assert.NoError(t, h.Wait())
+	select {
+	case <-h.Done():
+	default:
+		t.Error("Done should be closed after Wait returns")
+	}
+}
+
+func TestGo_Error(t *testing.T) {
+	tryErr := errors.New("goroutine error")
+
+	h := Go(func() error {
+		return tryErr
+	})
+
+	assert.Equal(t, tryErr, h.Wait())
+}
+
+func TestGo_PanicCaptured(t *testing.T) {
+	h := Go(func() error {
+		panic("goroutine panic")
+	}, WithName("worker"))
+
+	err := h.Wait()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "panic in spawned goroutine should not crash the process")
+	assert.Equal(t, "goroutine panic", panicErr.Value)
+	assert.Equal(t, "worker", panicErr.Name)
+}
+
+func TestGo_HooksApplied(t *testing.T) {
+	var caughtErr error
+
+	h := Go(func() error {
+		return errors.New("hooked")
+	}, WithHooks(Hooks{
+		OnTryEnd: func(err error) { caughtErr = err },
+	}))
+
+	assert.Error(t, h.Wait())
+	assert.EqualError(t, caughtErr, "hooked")
+}
+
+func TestGoCtx_ContextPassed(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "key", "value")
+
+	h := GoCtx(ctx, func(ctx context.Context) error {
+		if ctx.Value("key") != "value" {
+			return errors.New("context not passed")
+		}
+		return nil
+	})
+
+	assert.NoError(t, h.Wait())
+}
+
+func TestGoCtx_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	h := GoCtx(ctx, func(ctx context.Context) error {
+		return errors.New("should not execute")
+	})
+
+	assert.Equal(t, context.Canceled, h.Wait())
+}
+
+func TestTryCatchBlock_Go_CatchAndFinally(t *testing.T) {
+	var caughtErr error
+	finallyCalled := false
+
+	h := New().
+		Try(func() error {
+			panic("block panic")
+		}).
+		Catch(func(err error) {
+			caughtErr = err
+		}).
+		Finally(func() {
+			finallyCalled = true
+		}).
+		Go()
+
+	err := h.Wait()
+
+	assert.Error(t, err)
+	assert.Equal(t, err, caughtErr, "catch should run inside the spawned goroutine")
+	assert.True(t, finallyCalled, "finally should run inside the spawned goroutine")
+}
+
+func TestTryCatchBlock_Go_CatchPanicCaptured(t *testing.T) {
+	h := New().
+		Try(func() error {
+			return errors.New("error")
+		}).
+		Catch(func(err error) {
+			panic("panic in catch")
+		}).
+		Go()
+
+	err := h.Wait()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "catch panic should be returned instead of crashing")
+	assert.Equal(t, "panic in catch", panicErr.Value)
+}
+
+func TestGo_DoneChannel(t *testing.T) {
+	release := make(chan struct{})
+
+	h := Go(func() error {
+		<-release
+		return nil
+	})
+
+	select {
+	case <-h.Done():
+		t.Fatal("Done should not be closed while the goroutine is running")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-h.Done()
+	assert.NoError(t, h.Wait())
+}
+
+func TestGo_Goexit(t *testing.T) {
+	h := Go(func() error {
+		runtime.Goexit()
+		return nil
+	})
+	assert.ErrorIs(t, h.Wait(), ErrGoexit)
+}
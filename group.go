@@ -0,0 +1,133 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// GroupOption 定义 Group 的配置选项
+type GroupOption func(*Group)
+
+// WithGroupJoinErrors 使 Wait 返回所有任务错误通过 errors.Join 合并的结果，默认只返回第一个错误
+func WithGroupJoinErrors() GroupOption {
+	return func(g *Group) {
+		g.joinErrors = true
+	}
+}
+
+// WithGroupBlockOptions 为 Group 中每个任务的 TryCatchBlock 应用选项（名称、钩子等）
+func WithGroupBlockOptions(opts ...Option) GroupOption {
+	return func(g *Group) {
+		g.opts = append(g.opts, opts...)
+	}
+}
+
+// Group 类似 golang.org/x/sync/errgroup，但每个任务都以 TryCatchBlock 的语义执行，
+// 任务中的 panic 会转换为 *PanicError 而不会使进程崩溃
+// 零值的 Group 可以直接使用，此时不会在失败时取消 context
+type Group struct {
+	cancel     context.CancelCauseFunc // 第一个任务失败时取消共享 context
+	wg         sync.WaitGroup          // 等待所有任务结束
+	sem        chan struct{}           // 限制并发数的信号量，nil 表示不限制
+	opts       []Option                // 应用于每个任务的块选项
+	joinErrors bool                    // Wait 是否返回所有错误的合并结果
+	errOnce    sync.Once               // 保证只记录第一个错误
+	err        error                   // 第一个错误
+	mu         sync.Mutex              // 保护 errs
+	errs       []error                 // 所有任务的错误，仅在 joinErrors 时记录
+}
+
+// NewGroup 创建一个 Group，并返回从 ctx 派生的 context
+// 任一任务第一次返回错误（或发生 panic）时，派生的 context 会被取消，Wait 返回后也会被取消
+func NewGroup(ctx context.Context, opts ...GroupOption) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{cancel: cancel}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, ctx
+}
+
+// SetLimit 限制同时运行的任务数量，n 小于 0 表示不限制
+// 与 errgroup 一致，在仍有任务运行时修改限制会引发 panic
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic("gotrycatch: modify limit while goroutines in the group are still active")
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go 在新的 goroutine 中执行 fn，达到并发上限时阻塞直到有任务结束
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn)
+}
+
+// TryGo 仅在未达到并发上限时启动 fn，返回是否启动成功
+func (g *Group) TryGo(fn func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+// Wait 阻塞直到所有任务结束，返回第一个错误；使用 WithGroupJoinErrors 时返回所有错误的合并结果
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	if g.joinErrors {
+		return errors.Join(g.errs...)
+	}
+	return g.err
+}
+
+// start 启动一个任务，调用前需已获取信号量
+func (g *Group) start(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		tc := NewWithOptions(g.opts...).Try(fn)
+		tc.guardDo(tc.Do, func(err error) {
+			if err != nil {
+				g.fail(err)
+			}
+		})
+	}()
+}
+
+// fail 记录任务的错误，第一个错误会取消共享 context
+func (g *Group) fail(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		if g.cancel != nil {
+			g.cancel(err)
+		}
+	})
+	if g.joinErrors {
+		g.mu.Lock()
+		g.errs = append(g.errs, err)
+		g.mu.Unlock()
+	}
+}
+
+// done 在任务结束时释放信号量
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
@@ -0,0 +1,147 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup_AllSucceed(t *testing.T) {
+	g, _ := NewGroup(context.Background())
+	var count int32
+
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.Equal(t, int32(10), atomic.LoadInt32(&count))
+}
+
+func TestGroup_FirstErrorCancelsContext(t *testing.T) {
+	firstErr := errors.New("first failure")
+	g, ctx := NewGroup(context.Background())
+
+	g.Go(func() error {
+		return firstErr
+	})
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, firstErr, g.Wait())
+	assert.Equal(t, firstErr, context.Cause(ctx), "context should be cancelled with the first error")
+}
+
+func TestGroup_PanicBecomesError(t *testing.T) {
+	g, ctx := NewGroup(context.Background(), WithGroupBlockOptions(WithName("group-task")))
+
+	g.Go(func() error {
+		panic("task panic")
+	})
+
+	err := g.Wait()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "panic should be returned as *PanicError instead of crashing")
+	assert.Equal(t, "task panic", panicErr.Value)
+	assert.Equal(t, "group-task", panicErr.Name)
+	assert.Error(t, ctx.Err(), "panic should cancel the shared context")
+}
+
+func TestGroup_JoinErrors(t *testing.T) {
+	errA := errors.New("error a")
+	errB := errors.New("error b")
+	g, _ := NewGroup(context.Background(), WithGroupJoinErrors())
+
+	g.Go(func() error { return errA })
+	g.Go(func() error { return errB })
+	g.Go(func() error { return nil })
+
+	err := g.Wait()
+
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+	var running, maxRunning int32
+
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2), "concurrency should not exceed the limit")
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	var g Group
+	g.SetLimit(1)
+	release := make(chan struct{})
+
+	assert.True(t, g.TryGo(func() error {
+		<-release
+		return nil
+	}))
+	assert.False(t, g.TryGo(func() error { return nil }), "TryGo should fail when the limit is reached")
+
+	close(release)
+	assert.NoError(t, g.Wait())
+	assert.True(t, g.TryGo(func() error { return nil }), "TryGo should succeed after tasks finish")
+	assert.NoError(t, g.Wait())
+}
+
+func TestGroup_ZeroValue(t *testing.T) {
+	var g Group
+	tryErr := errors.New("zero value error")
+
+	g.Go(func() error { return tryErr })
+
+	assert.Equal(t, tryErr, g.Wait())
+}
+
+func TestGroup_HooksAppliedToEachTask(t *testing.T) {
+	var tryStarts int32
+	g, _ := NewGroup(context.Background(), WithGroupBlockOptions(WithHooks(Hooks{
+		OnTryStart: func() { atomic.AddInt32(&tryStarts, 1) },
+	})))
+
+	for i := 0; i < 5; i++ {
+		g.Go(func() error { return nil })
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.Equal(t, int32(5), atomic.LoadInt32(&tryStarts))
+}
+
+func TestGroup_Goexit(t *testing.T) {
+	g, _ := NewGroup(context.Background())
+	g.Go(func() error {
+		runtime.Goexit()
+		return nil
+	})
+	assert.ErrorIs(t, g.Wait(), ErrGoexit)
+}
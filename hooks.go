@@ -0,0 +1,103 @@
+package gotrycatch
+
+import "context"
+
+// hookTryStart 依次调用默认与块自身的 OnTryStart 钩子，并通知观察者第 attempt 次尝试开始
+// 没有任何钩子与观察者时直接返回，使无钩子的热路径不产生函数调用
+func (tc *TryCatchBlock) hookTryStart(d *defaults, ctx context.Context, attempt int) {
+	if d != nil || tc.hooks.OnTryStart != nil || tc.opts != nil {
+		tc.notifyTryStart(d, ctx, attempt)
+	}
+}
+
+// notifyTryStart 是 hookTryStart 的实现
+func (tc *TryCatchBlock) notifyTryStart(d *defaults, ctx context.Context, attempt int) {
+	d.onTryStart()
+	if tc.hooks.OnTryStart != nil {
+		tc.hooks.OnTryStart()
+	}
+	if tc.observed() {
+		tc.observeTryStart(ctx, attempt)
+	}
+}
+
+// hookTryEnd 依次调用默认与块自身的 OnTryEnd 钩子，并通知观察者本次尝试结束
+func (tc *TryCatchBlock) hookTryEnd(d *defaults, err error) {
+	if d != nil || tc.hooks.OnTryEnd != nil || tc.opts != nil {
+		tc.notifyTryEnd(d, err)
+	}
+}
+
+// notifyTryEnd 是 hookTryEnd 的实现
+func (tc *TryCatchBlock) notifyTryEnd(d *defaults, err error) {
+	tc.hookTryEndOnly(d, err)
+	if tc.observed() {
+		tc.observeTryEnd(err, nil)
+	}
+}
+
+// hookTryEndOnly 依次调用默认与块自身的 OnTryEnd 钩子，不通知观察者
+// 用于 Do 的 defer 中，观察者的 OnTryEnd 由受保护的 observeDone 补发
+func (tc *TryCatchBlock) hookTryEndOnly(d *defaults, err error) {
+	d.onTryEnd(err)
+	if tc.hooks.OnTryEnd != nil {
+		tc.hooks.OnTryEnd(err)
+	}
+}
+
+// hookCatch 依次调用默认与块自身的 OnCatch 钩子
+func (tc *TryCatchBlock) hookCatch(d *defaults, err error) {
+	d.onCatch(err)
+	if tc.hooks.OnCatch != nil {
+		tc.hooks.OnCatch(err)
+	}
+}
+
+// hookFinally 在隔离环境中依次调用默认与块自身的 OnFinally 钩子，返回钩子中 panic 转换得到的错误
+func (tc *TryCatchBlock) hookFinally(d *defaults, converter PanicConverter) error {
+	if d == nil && tc.hooks.OnFinally == nil {
+		return nil
+	}
+	return tc.notifyFinally(d, converter)
+}
+
+// notifyFinally 是 hookFinally 的实现
+func (tc *TryCatchBlock) notifyFinally(d *defaults, converter PanicConverter) error {
+	err := d.onFinally(tc.name, converter)
+	if tc.hooks.OnFinally != nil {
+		err = joinErrors(err, finallyGuard(tc.hooks.OnFinally, nil, tc.name, d, converter))
+	}
+	return err
+}
+
+// hookAbandon 依次调用默认与块自身的 OnAbandon 钩子
+func (tc *TryCatchBlock) hookAbandon(d *defaults, err error) {
+	d.onAbandon(err)
+	if tc.hooks.OnAbandon != nil {
+		tc.hooks.OnAbandon(err)
+	}
+}
+
+// hookRetry 依次调用默认与块自身的 OnRetry 钩子
+func (tc *TryCatchBlock) hookRetry(d *defaults, attempt int, err error) {
+	d.onRetry(attempt, err)
+	if tc.hooks.OnRetry != nil {
+		tc.hooks.OnRetry(attempt, err)
+	}
+}
+
+// hookCatchPanic 依次调用默认与块自身的 OnCatchPanic 钩子
+func (tc *TryCatchBlock) hookCatchPanic(d *defaults, recovered any) {
+	d.onCatchPanic(recovered)
+	if tc.hooks.OnCatchPanic != nil {
+		tc.hooks.OnCatchPanic(recovered)
+	}
+}
+
+// hookCancel 依次调用默认与块自身的 OnCancel 钩子
+func (tc *TryCatchBlock) hookCancel(d *defaults, err error) {
+	d.onCancel(err)
+	if tc.hooks.OnCancel != nil {
+		tc.hooks.OnCancel(err)
+	}
+}
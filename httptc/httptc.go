@@ -0,0 +1,243 @@
+// Package httptc 为 net/http 提供基于 gotrycatch 的恢复中间件
+//
+// Middleware 在 gtc.TryCatchBlock 中执行每个请求：处理函数中的 panic 与通过 gtc.Throw 抛出的错误
+// 交给可替换的 ErrorMapper 写入响应，默认输出 RFC 7807 的 application/problem+json。
+// 块按路由命名，因此钩子、观察者、熔断器等块级选项都可以按路由生效；默认名称只取有限的取值，不会随请求路径无限增长
+package httptc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+)
+
+// ProblemContentType 是 RFC 7807 problem details 的媒体类型
+const ProblemContentType = "application/problem+json"
+
+// Problem 是 RFC 7807 定义的 problem details，同时实现了 error
+// 处理函数可以通过 gtc.Throw(&httptc.Problem{...}) 直接结束请求并指定响应
+type Problem struct {
+	Type     string `json:"type,omitempty"`     // 问题类型的 URI，为空时等价于 "about:blank"
+	Title    string `json:"title,omitempty"`    // 问题类型的简短描述，为空时使用状态码的标准描述
+	Status   int    `json:"status,omitempty"`   // HTTP 状态码，为 0 时使用 500
+	Detail   string `json:"detail,omitempty"`   // 本次问题的具体说明
+	Instance string `json:"instance,omitempty"` // 本次问题的 URI
+}
+
+// Error 返回问题的描述
+func (p *Problem) Error() string {
+	status := p.status()
+	msg := strconv.Itoa(status) + " " + p.title(status)
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+	return msg
+}
+
+// status 返回响应使用的状态码
+func (p *Problem) status() int {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return p.Status
+}
+
+// title 返回响应使用的标题
+func (p *Problem) title(status int) string {
+	if p.Title == "" {
+		return http.StatusText(status)
+	}
+	return p.Title
+}
+
+// ErrorMapper 将请求执行中的错误写入响应
+// 只有在处理函数尚未开始写入响应时才会被调用，因此可以自由设置响应头与状态码
+type ErrorMapper func(w http.ResponseWriter, r *http.Request, err error)
+
+// WriteProblem 是默认的 ErrorMapper，将 err 以 application/problem+json 格式写入响应
+// err 为（或包装了）*Problem 时按其内容输出；其他错误只输出状态码及其标准描述，不暴露内部的错误信息：
+// gtc.ErrBulkheadFull 与 gtc.ErrCircuitOpen 对应 503，context.DeadlineExceeded 对应 504，其余为 500
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var p *Problem
+	if !errors.As(err, &p) {
+		p = &Problem{Status: StatusOf(err)}
+	}
+	status := p.status()
+	body := *p
+	body.Status, body.Title = status, p.title(status)
+
+	h := w.Header()
+	h.Set("Content-Type", ProblemContentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Del("Content-Length")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// StatusOf 返回 err 对应的 HTTP 状态码
+func StatusOf(err error) int {
+	var p *Problem
+	switch {
+	case errors.As(err, &p):
+		return p.status()
+	case errors.Is(err, gtc.ErrBulkheadFull), errors.Is(err, gtc.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// config 保存中间件的配置
+type config struct {
+	mapper    ErrorMapper
+	route     func(*http.Request) string
+	blockOpts []gtc.Option
+}
+
+// Option 定义中间件的配置选项
+type Option func(*config)
+
+// WithErrorMapper 指定将错误写入响应的函数，默认为 WriteProblem
+func WithErrorMapper(mapper ErrorMapper) Option {
+	return func(c *config) {
+		if mapper != nil {
+			c.mapper = mapper
+		}
+	}
+}
+
+// WithRouteName 指定块名称的计算方式
+// 默认为请求匹配的路由模板（Go 1.23 起的 r.Pattern，需要在 ServeMux 内部按路由包裹处理函数），没有模板时为 r.Method。
+// 块名称会成为指标标签、expvar 键与日志字段，返回值的取值应当是有限的；确实需要原始路径时可以使用 PathRoute
+func WithRouteName(route func(r *http.Request) string) Option {
+	return func(c *config) {
+		if route != nil {
+			c.route = route
+		}
+	}
+}
+
+// WithBlockOptions 指定执行请求的块使用的选项，例如钩子、观察者、超时、熔断器与隔板
+// 块的名称与 context 由中间件按请求设置，opts 中的 WithName / WithContext 不生效。
+// 不建议使用 gtc.WithEnforceDeadline：被放弃的处理函数仍会写入同一个 ResponseWriter，需要硬超时时请使用 http.TimeoutHandler
+func WithBlockOptions(opts ...gtc.Option) Option {
+	return func(c *config) {
+		c.blockOpts = append(c.blockOpts, opts...)
+	}
+}
+
+// Middleware 返回在 gtc.TryCatchBlock 中执行 next 的 http.Handler
+//
+// 处理函数中的 panic（转换为 *gtc.PanicError）、通过 gtc.Throw 抛出的错误，以及块拒绝执行的错误
+// （熔断、隔板已满、超时）交给 ErrorMapper 写入响应。处理函数收到的请求携带块的 context，
+// 可以通过 gtc.ScopeFrom(r.Context()) 注册清理函数。
+//
+// http.ErrAbortHandler 会被原样重新抛出，由 net/http 静默地中止响应。
+// 处理函数已经开始写入响应时无法再输出错误响应，此时同样以 http.ErrAbortHandler 中止连接，
+// 避免客户端把被截断的响应当成完整的响应；请求的 context 已经结束（例如客户端断开）时不写入任何内容
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	cfg := config{
+		mapper: WriteProblem,
+		route:  defaultRoute,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &handler{next: next, cfg: cfg}
+}
+
+// defaultRoute 返回默认的块名称：请求匹配的路由模板，没有模板时为请求方法
+func defaultRoute(r *http.Request) string {
+	if pattern := routePattern(r); pattern != "" {
+		return pattern
+	}
+	return r.Method
+}
+
+// PathRoute 以 r.Method + " " + r.URL.Path 作为块名称，供 WithRouteName 使用
+// 每个不同的路径都会产生新的名称，只应在路径取值有限（例如没有路径参数）的服务中使用
+func PathRoute(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// handler 是 Middleware 返回的 http.Handler
+type handler struct {
+	next http.Handler
+	cfg  config
+}
+
+// ServeHTTP 在块中执行请求，并将错误写入响应
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &responseWriter{ResponseWriter: w}
+	err := gtc.NewWithOptions(h.cfg.blockOpts...).
+		ApplyOptions(gtc.WithName(h.cfg.route(r)), gtc.WithContext(r.Context()), repanicAbort).
+		TryCtx(func(ctx context.Context) error {
+			h.next.ServeHTTP(rw, r.WithContext(ctx))
+			return nil
+		}).
+		Do()
+	if err == nil || r.Context().Err() != nil {
+		return
+	}
+	if rw.wroteHeader {
+		panic(http.ErrAbortHandler)
+	}
+	h.cfg.mapper(rw, r, err)
+}
+
+// repanicAbort 在块已有的重新抛出策略之外，重新抛出 http.ErrAbortHandler
+func repanicAbort(tc *gtc.TryCatchBlock) {
+	prev := tc.RepanicPolicy()
+	tc.ApplyOptions(gtc.WithRepanicPolicy(func(recovered any) bool {
+		return recovered == http.ErrAbortHandler || (prev != nil && prev(recovered))
+	}))
+}
+
+// responseWriter 记录处理函数是否已经开始写入响应
+// 通过 Unwrap 支持 http.ResponseController 访问底层 ResponseWriter 的能力
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader 写入状态码；1xx 的信息性响应（101 除外）之后仍然可以写入最终的响应
+func (w *responseWriter) WriteHeader(code int) {
+	if code >= 200 || code == http.StatusSwitchingProtocols {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 写入响应体，未写入状态码时隐式写入 200
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush 将缓冲的数据发送给客户端，底层不支持时什么也不做
+func (w *responseWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack 接管底层连接，之后不再写入错误响应
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, buf, err
+}
+
+// Unwrap 返回底层的 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,245 @@
+package httptc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"github.com/stretchr/testify/assert"
+)
+
+// nameObserver 记录执行结束时块的名称与结果
+type nameObserver struct {
+	infos []gtc.ExecInfo
+}
+
+func (o *nameObserver) OnTryStart(gtc.ExecInfo)  {}
+func (o *nameObserver) OnTryEnd(gtc.ExecInfo)    {}
+func (o *nameObserver) OnDone(info gtc.ExecInfo) { o.infos = append(o.infos, info) }
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestMiddleware_Success(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodPost, "/items", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("secret internal state")
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, map[string]any{
+		"title":  "Internal Server Error",
+		"status": float64(500),
+	}, decodeProblem(t, rec), "panic details should not leak into the response")
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+}
+
+func TestMiddleware_ThrowProblem(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		gtc.Throw(&Problem{
+			Type:     "https://example.com/probs/not-found",
+			Status:   http.StatusNotFound,
+			Detail:   "user 42 not found",
+			Instance: r.URL.Path,
+		})
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Length"), "a stale Content-Length should be removed")
+	assert.Equal(t, map[string]any{
+		"type":     "https://example.com/probs/not-found",
+		"title":    "Not Found",
+		"status":   float64(404),
+		"detail":   "user 42 not found",
+		"instance": "/users/42",
+	}, decodeProblem(t, rec))
+}
+
+func TestMiddleware_ThrowError(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		gtc.Throw(errors.New("database is down"))
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "database")
+}
+
+func TestMiddleware_AlreadyWritten(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		panic("boom")
+	}))
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	}, "a started response should be aborted instead of written twice")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "partial", rec.Body.String())
+}
+
+func TestMiddleware_ErrAbortHandler(t *testing.T) {
+	finally := false
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), WithBlockOptions(gtc.WithHooks(gtc.Hooks{OnFinally: func() { finally = true }})))
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.True(t, finally)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestMiddleware_KeepsRepanicPolicy(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("bug")
+	}), WithBlockOptions(gtc.WithRepanicPolicy(func(v any) bool { return v == "bug" })))
+
+	assert.PanicsWithValue(t, "bug", func() {
+		serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestMiddleware_ErrorMapper(t *testing.T) {
+	var mapped error
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}), WithErrorMapper(func(w http.ResponseWriter, r *http.Request, err error) {
+		mapped = err
+		http.Error(w, "oops", http.StatusTeapot)
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	var pe *gtc.PanicError
+	assert.ErrorAs(t, mapped, &pe)
+	assert.Equal(t, "boom", pe.Value)
+}
+
+func TestMiddleware_RouteName(t *testing.T) {
+	obs := &nameObserver{}
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	serve(Middleware(ok, WithBlockOptions(gtc.WithObserver(obs), gtc.WithName("ignored"))),
+		httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	serve(Middleware(ok, WithBlockOptions(gtc.WithObserver(obs)), WithRouteName(func(*http.Request) string {
+		return "GET /users/{id}"
+	})), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	serve(Middleware(ok, WithBlockOptions(gtc.WithObserver(obs)), WithRouteName(PathRoute)),
+		httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if assert.Len(t, obs.infos, 3) {
+		assert.Equal(t, "GET", obs.infos[0].Name, "without a route pattern the default name is the method only")
+		assert.Equal(t, "GET /users/{id}", obs.infos[1].Name)
+		assert.Equal(t, gtc.OutcomeSuccess, obs.infos[1].Outcome)
+		assert.Equal(t, "GET /users/42", obs.infos[2].Name)
+	}
+}
+
+func TestMiddleware_Rejections(t *testing.T) {
+	bulkhead := gtc.NewBulkhead(1, 0, 0)
+	assert.NoError(t, bulkhead.Acquire(context.Background()))
+	defer bulkhead.Release()
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	rec := serve(Middleware(ok, WithBlockOptions(gtc.WithBulkhead(bulkhead))), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-r.Context().Done() })
+	rec = serve(Middleware(slow, WithBlockOptions(gtc.WithTimeout(10*time.Millisecond), gtc.WithEnforceDeadline())),
+		httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+}
+
+func TestMiddleware_ClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		cancel()
+		panic("boom")
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	assert.Empty(t, rec.Body.String(), "nothing should be written once the request context is done")
+}
+
+func TestMiddleware_Scope(t *testing.T) {
+	closed := false
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gtc.ScopeFrom(r.Context()).Defer(func() { closed = true })
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.True(t, closed)
+}
+
+func TestResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &responseWriter{ResponseWriter: rec}
+
+	w.WriteHeader(http.StatusEarlyHints)
+	assert.False(t, w.wroteHeader, "an informational response should not commit the response")
+
+	assert.NoError(t, http.NewResponseController(w).Flush())
+	assert.True(t, w.wroteHeader)
+	assert.True(t, rec.Flushed)
+	assert.Same(t, rec, w.Unwrap())
+
+	_, _, err := w.Hijack()
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+}
+
+func TestStatusOf(t *testing.T) {
+	assert.Equal(t, http.StatusServiceUnavailable, StatusOf(gtc.ErrBulkheadFull))
+	assert.Equal(t, http.StatusServiceUnavailable, StatusOf(gtc.ErrCircuitOpen))
+	assert.Equal(t, http.StatusGatewayTimeout, StatusOf(context.DeadlineExceeded))
+	assert.Equal(t, http.StatusBadRequest, StatusOf(&Problem{Status: http.StatusBadRequest}))
+	assert.Equal(t, http.StatusInternalServerError, StatusOf(&Problem{}))
+	assert.Equal(t, http.StatusInternalServerError, StatusOf(errors.New("error")))
+}
+
+func TestProblem_Error(t *testing.T) {
+	assert.Equal(t, "404 Not Found: user 42 not found", (&Problem{Status: 404, Detail: "user 42 not found"}).Error())
+	assert.Equal(t, "409 Version Conflict", (&Problem{Status: 409, Title: "Version Conflict"}).Error())
+	assert.Equal(t, "500 Internal Server Error", (&Problem{}).Error())
+}
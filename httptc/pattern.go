@@ -0,0 +1,10 @@
+//go:build go1.23
+
+package httptc
+
+import "net/http"
+
+// routePattern 返回 ServeMux 为请求匹配的路由模板，请求未经过 ServeMux 路由时为空
+func routePattern(r *http.Request) string {
+	return r.Pattern
+}
@@ -0,0 +1,10 @@
+//go:build !go1.23
+
+package httptc
+
+import "net/http"
+
+// routePattern 在 Go 1.23 之前没有 r.Pattern，总是返回空
+func routePattern(*http.Request) string {
+	return ""
+}
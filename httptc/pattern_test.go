@@ -0,0 +1,30 @@
+//go:build go1.23
+
+// 模块声明的 go 版本低于 1.22，需要显式启用支持方法与通配符的 ServeMux 路由
+//go:debug httpmuxgo121=0
+
+package httptc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_RoutePattern(t *testing.T) {
+	obs := &nameObserver{}
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		WithBlockOptions(gtc.WithObserver(obs))))
+
+	serve(mux, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	serve(mux, httptest.NewRequest(http.MethodGet, "/users/43", nil))
+
+	if assert.Len(t, obs.infos, 2) {
+		assert.Equal(t, "GET /users/{id}", obs.infos[0].Name)
+		assert.Equal(t, obs.infos[0].Name, obs.infos[1].Name, "different paths should share the route name")
+	}
+}
@@ -0,0 +1,47 @@
+package gotrycatch
+
+import "time"
+
+// Metrics 接收块执行的指标，所有方法都以块名称（Name()）作为标签
+// 实现需要是并发安全的，同一个 Metrics 通常被多个块共享；现成的实现见 expvartc 与 promtc
+type Metrics interface {
+	IncExecutions(name string)                    // 每次 Do 结束时调用一次（包括 context 在执行前已结束的情况）
+	IncErrors(name string)                        // try 最终返回错误时调用
+	IncPanics(name string)                        // try 最终发生 panic 时调用
+	IncCatchPanics(name string)                   // catch 自身发生 panic 时调用
+	IncCancellations(name string)                 // try 因 context 结束而失败时调用（执行前已取消、超时或强制截止模式下被放弃）
+	ObserveDuration(name string, d time.Duration) // 记录 try 的耗时，重试时为第一次尝试开始到最后一次尝试结束
+}
+
+// WithMetrics 为块记录执行指标，基于 Observer 实现，不影响块已有的钩子
+func WithMetrics(m Metrics) Option {
+	return WithObserver(metricsObserver{metrics: m})
+}
+
+// metricsObserver 将执行结果转换为 Metrics 调用
+type metricsObserver struct {
+	metrics Metrics
+}
+
+func (metricsObserver) OnTryStart(ExecInfo) {}
+
+func (metricsObserver) OnTryEnd(ExecInfo) {}
+
+// OnDone 汇总本次 Do 的指标
+func (o metricsObserver) OnDone(info ExecInfo) {
+	o.metrics.IncExecutions(info.Name)
+	if !info.Start.IsZero() {
+		o.metrics.ObserveDuration(info.Name, info.Duration())
+	}
+	switch info.Outcome {
+	case OutcomeError:
+		o.metrics.IncErrors(info.Name)
+	case OutcomePanic:
+		o.metrics.IncPanics(info.Name)
+	case OutcomeCancelled:
+		o.metrics.IncCancellations(info.Name)
+	}
+	if info.CatchPanic != nil {
+		o.metrics.IncCatchPanics(info.Name)
+	}
+}
@@ -0,0 +1,125 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMetrics 记录各指标按名称的计数
+type fakeMetrics struct {
+	mu        sync.Mutex
+	counts    map[string]int
+	durations []time.Duration
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{counts: make(map[string]int)}
+}
+
+func (m *fakeMetrics) inc(kind, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[kind+":"+name]++
+}
+
+func (m *fakeMetrics) IncExecutions(name string)    { m.inc("executions", name) }
+func (m *fakeMetrics) IncErrors(name string)        { m.inc("errors", name) }
+func (m *fakeMetrics) IncPanics(name string)        { m.inc("panics", name) }
+func (m *fakeMetrics) IncCatchPanics(name string)   { m.inc("catch_panics", name) }
+func (m *fakeMetrics) IncCancellations(name string) { m.inc("cancellations", name) }
+
+func (m *fakeMetrics) ObserveDuration(_ string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations = append(m.durations, d)
+}
+
+func TestWithMetrics_Outcomes(t *testing.T) {
+	m := newFakeMetrics()
+	opts := []Option{WithName("op"), WithMetrics(m)}
+
+	_ = NewWithOptions(opts...).Try(func() error { return nil }).Do()
+	_ = NewWithOptions(opts...).Try(func() error { return errors.New("boom") }).Do()
+	_ = NewWithOptions(opts...).Try(func() error { panic("boom") }).Do()
+
+	assert.Equal(t, map[string]int{
+		"executions:op": 3,
+		"errors:op":     1,
+		"panics:op":     1,
+	}, m.counts)
+	assert.Len(t, m.durations, 3)
+}
+
+func TestWithMetrics_Duration(t *testing.T) {
+	m := newFakeMetrics()
+
+	_ = NewWithOptions(WithMetrics(m)).
+		Try(func() error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}).
+		Do()
+
+	assert.Len(t, m.durations, 1)
+	assert.GreaterOrEqual(t, m.durations[0], 10*time.Millisecond)
+}
+
+func TestWithMetrics_CatchPanic(t *testing.T) {
+	m := newFakeMetrics()
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithName("op"), WithMetrics(m)).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	assert.Equal(t, 1, m.counts["catch_panics:op"])
+	assert.Equal(t, 1, m.counts["errors:op"])
+	assert.Equal(t, 1, m.counts["executions:op"])
+}
+
+func TestWithMetrics_Cancelled(t *testing.T) {
+	m := newFakeMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = NewWithOptions(WithContext(ctx), WithName("op"), WithMetrics(m)).
+		Try(func() error { return nil }).
+		Do()
+
+	assert.Equal(t, map[string]int{
+		"executions:op":    1,
+		"cancellations:op": 1,
+	}, m.counts)
+	assert.Empty(t, m.durations, "try did not run")
+}
+
+func TestWithMetrics_RetryCountsOnce(t *testing.T) {
+	m := newFakeMetrics()
+
+	_ = NewWithOptions(WithMetrics(m), WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() error { return errors.New("boom") }).
+		Do()
+
+	assert.Equal(t, 1, m.counts["executions:"])
+	assert.Equal(t, 1, m.counts["errors:"])
+	assert.Len(t, m.durations, 1)
+}
+
+func TestWithMetrics_KeepsExistingHooks(t *testing.T) {
+	m := newFakeMetrics()
+	var started bool
+
+	_ = NewWithOptions(WithHooks(Hooks{OnTryStart: func() { started = true }}), WithMetrics(m)).
+		Try(func() error { return nil }).
+		Do()
+
+	assert.True(t, started)
+	assert.Equal(t, 1, m.counts["executions:"])
+}
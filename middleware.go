@@ -0,0 +1,41 @@
+package gotrycatch
+
+import "context"
+
+// Middleware 包裹 try 的调用，用于限流、tracing、向 context 注入租户信息、鉴权等横切逻辑
+// next 为内层的调用（最内层为 try / tryCtx），中间件可以修改传给 next 的 context、
+// 在调用前后执行逻辑，或者不调用 next 而直接返回错误
+type Middleware func(next func(context.Context) error) func(context.Context) error
+
+// WithMiddleware 为块添加中间件，可以多次使用
+// 中间件按添加顺序由外向内包裹 try，第一个添加的位于最外层；整条调用链都处于 panic 恢复的保护范围内，
+// 配置重试时每次尝试都会经过完整的调用链
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(tc *TryCatchBlock) {
+		for _, mw := range middlewares {
+			if mw != nil {
+				o := tc.mutableOptions()
+				o.middlewares = append(o.middlewares, mw)
+			}
+		}
+	}
+}
+
+// callChain 经过中间件调用 try 或 tryCtx，没有中间件时直接调用（可被内联）
+func callChain(ctx context.Context, try func() error, tryCtx func(context.Context) error, middlewares []Middleware) error {
+	if len(middlewares) == 0 {
+		return callTry(ctx, try, tryCtx)
+	}
+	return callMiddlewares(ctx, try, tryCtx, middlewares)
+}
+
+// callMiddlewares 由外向内经过中间件调用 try 或 tryCtx
+func callMiddlewares(ctx context.Context, try func() error, tryCtx func(context.Context) error, middlewares []Middleware) error {
+	next := func(ctx context.Context) error {
+		return callTry(ctx, try, tryCtx)
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+	return next(ctx)
+}
@@ -0,0 +1,158 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+// tracing 返回记录进入与退出顺序的中间件
+func tracing(name string, order *[]string) Middleware {
+	return func(next func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			*order = append(*order, name+":before")
+			err := next(ctx)
+			*order = append(*order, name+":after")
+			return err
+		}
+	}
+}
+
+func TestWithMiddleware_Order(t *testing.T) {
+	var order []string
+
+	err := NewWithOptions(WithMiddleware(tracing("a", &order), tracing("b", &order)), WithMiddleware(tracing("c", &order))).
+		Try(func() error {
+			order = append(order, "try")
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a:before", "b:before", "c:before", "try", "c:after", "b:after", "a:after"}, order)
+}
+
+func TestWithMiddleware_InjectsContext(t *testing.T) {
+	inject := func(next func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			return next(context.WithValue(ctx, tenantKey{}, "acme"))
+		}
+	}
+
+	var tenant any
+	err := NewWithOptions(WithMiddleware(inject)).
+		TryCtx(func(ctx context.Context) error {
+			tenant = ctx.Value(tenantKey{})
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestWithMiddleware_ShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+	deny := func(func(context.Context) error) func(context.Context) error {
+		return func(context.Context) error { return errDenied }
+	}
+
+	var tryCalled bool
+	var caught error
+	err := NewWithOptions(WithMiddleware(deny)).
+		Try(func() error {
+			tryCalled = true
+			return nil
+		}).
+		Catch(func(err error) { caught = err }).
+		Do()
+
+	assert.False(t, tryCalled)
+	assert.ErrorIs(t, err, errDenied)
+	assert.ErrorIs(t, caught, errDenied, "middleware errors go through catch")
+}
+
+func TestWithMiddleware_PanicRecovered(t *testing.T) {
+	boom := func(func(context.Context) error) func(context.Context) error {
+		return func(context.Context) error { panic("middleware boom") }
+	}
+
+	var finallyCalled bool
+	err := NewWithOptions(WithMiddleware(boom)).
+		Try(func() error { return nil }).
+		Finally(func() { finallyCalled = true }).
+		Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "middleware boom", panicErr.Value)
+	assert.True(t, finallyCalled)
+}
+
+func TestWithMiddleware_SeesTryPanic(t *testing.T) {
+	var sawPanic bool
+	observe := func(next func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			defer func() {
+				if r := recover(); r != nil {
+					sawPanic = true
+					panic(r)
+				}
+			}()
+			return next(ctx)
+		}
+	}
+
+	err := NewWithOptions(WithMiddleware(observe)).
+		Try(func() error { panic("try boom") }).
+		Do()
+
+	assert.True(t, sawPanic)
+	assert.ErrorAs(t, err, new(*PanicError))
+}
+
+func TestWithMiddleware_EachRetryAttempt(t *testing.T) {
+	var calls int
+	count := func(next func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			calls++
+			return next(ctx)
+		}
+	}
+
+	_ = NewWithOptions(WithMiddleware(count), WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() error { return errors.New("boom") }).
+		Do()
+
+	assert.Equal(t, 3, calls)
+}
+
+func TestWithMiddleware_EnforceDeadline(t *testing.T) {
+	var order []string
+
+	err := NewWithOptions(WithTimeout(time.Second), WithEnforceDeadline(), WithMiddleware(tracing("a", &order))).
+		Try(func() error {
+			order = append(order, "try")
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a:before", "try", "a:after"}, order)
+}
+
+func TestWithMiddleware_NilSkippedAndReset(t *testing.T) {
+	var order []string
+	tc := NewWithOptions(WithMiddleware(nil, tracing("a", &order)))
+	assert.Len(t, tc.options().middlewares, 1)
+
+	tc.Reset()
+	assert.Empty(t, tc.options().middlewares)
+	_ = tc.Try(func() error { return nil }).Do()
+	assert.Empty(t, order)
+}
@@ -0,0 +1,229 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Outcome 表示一次执行的结果类别
+type Outcome int
+
+const (
+	OutcomeSuccess   Outcome = iota // try 正常返回 nil
+	OutcomeError                    // try 返回错误
+	OutcomePanic                    // try 发生 panic
+	OutcomeCancelled                // context 结束：执行前已取消、超时或强制截止模式下被放弃
+)
+
+// String 返回结果类别的名称
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeError:
+		return "error"
+	case OutcomePanic:
+		return "panic"
+	case OutcomeCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// outcomeOf 根据错误与 panic 值判断结果类别；错误来自 ctx 自身的结束时视为取消
+func outcomeOf(ctx context.Context, err error, panicVal any) Outcome {
+	switch {
+	case panicVal != nil:
+		return OutcomePanic
+	case err == nil:
+		return OutcomeSuccess
+	case ctx != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()):
+		return OutcomeCancelled
+	default:
+		return OutcomeError
+	}
+}
+
+// ExecInfo 描述块的一次执行，按值传给 Observer
+type ExecInfo struct {
+	Name       string          // 块的名称
+	Context    context.Context // 传给 try 的 context（包含 WithTimeout 派生的超时）；try 未执行时为块的 context，可能为 nil
+	Start      time.Time       // 第一次尝试开始的时间，try 未执行时为零值
+	End        time.Time       // 最近一次尝试结束的时间，尝试进行中为零值
+	Attempt    int             // 尝试序号（从 1 开始）；OnDone 中为实际执行的尝试次数，try 未执行时为 0
+	Outcome    Outcome         // 结果类别
+	Err        error           // try 的错误，发生 panic 时为转换得到的错误；均为 catch 处理之前的值
+	PanicValue any             // try 的原始 panic 值
+	Abandoned  bool            // 尝试是否在强制截止模式下被放弃（此时 Outcome 为 OutcomeCancelled）
+	CatchPanic any             // catch 自身的 panic 值，仅在 OnDone 与 OnFinally 中设置
+	FinallyErr error           // Scope 清理函数与 finally 的错误（含其中 panic 转换得到的错误），仅在 OnFinally 中设置
+}
+
+// Duration 返回从第一次尝试开始到最近一次尝试结束的耗时，try 未执行或尚未结束时返回 0
+func (i ExecInfo) Duration() time.Duration {
+	if i.Start.IsZero() || i.End.IsZero() {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Observer 观察块的执行，与 Hooks 不同，每个回调都会收到携带块元数据、耗时与结果的 ExecInfo
+// 回调在执行 Do 的 goroutine 中同步调用；同一个 Observer 被多个块共享时需要是并发安全的。
+// OnTryStart 与 OnTryEnd 中的 panic 按 try 的 panic 处理；OnDone 中的 panic 被转换为错误并合并到 Do 的返回值中，不会跳过 finally
+type Observer interface {
+	OnTryStart(info ExecInfo) // 每次尝试开始前调用
+	OnTryEnd(info ExecInfo)   // 每次尝试结束后调用，包括发生 panic 的尝试
+	OnDone(info ExecInfo)     // 每次 Do 在 catch 之后、finally 之前调用一次，携带最终结果；context 在执行前已结束时同样调用
+}
+
+// FinallyObserver 是 Observer 的可选扩展，观察者实现它时在 Scope 清理函数与 finally 执行之后收到通知
+// OnFinally 每次 Do 调用一次（包括 catch 发生 panic 或 panic 被重新抛出的情况），与 OnFinally 钩子不同，它不会被 WithHooks 覆盖；
+// 其中的 panic 同样被转换为错误并合并到 Do 的返回值中
+type FinallyObserver interface {
+	OnFinally(info ExecInfo) // 携带与 OnDone 相同的结果，以及 FinallyErr
+}
+
+// WithObserver 为块添加观察者，可以多次使用，观察者按添加顺序调用
+func WithObserver(observers ...Observer) Option {
+	return func(tc *TryCatchBlock) {
+		if len(observers) > 0 {
+			o := tc.mutableOptions()
+			o.observers = append(o.observers, observers...)
+		}
+	}
+}
+
+// HooksObserver 将 Hooks 适配为 Observer，便于只接受 Observer 的组件复用已有的钩子
+// OnTryStart 与 OnTryEnd 对应每次尝试（发生 panic 的尝试不调用 OnTryEnd，与 Hooks 一致），
+// OnDone 依次对应 OnCancel（执行前已取消）或 OnCatch（try 失败）、OnCatchPanic 与 OnFinally；
+// 与 WithHooks 不同，OnCatch 在没有设置 catch 时也会调用，OnRetry 与 OnAbandon 没有对应的回调
+// （观察者可以从 Attempt 大于 1 的 OnTryStart 与 ExecInfo.Abandoned 得到同样的信息）
+func HooksObserver(h Hooks) Observer {
+	return hooksObserver{hooks: h}
+}
+
+// hooksObserver 是 HooksObserver 返回的适配器
+type hooksObserver struct {
+	hooks Hooks
+}
+
+func (o hooksObserver) OnTryStart(ExecInfo) {
+	if o.hooks.OnTryStart != nil {
+		o.hooks.OnTryStart()
+	}
+}
+
+func (o hooksObserver) OnTryEnd(info ExecInfo) {
+	if o.hooks.OnTryEnd != nil && info.Outcome != OutcomePanic {
+		o.hooks.OnTryEnd(info.Err)
+	}
+}
+
+func (o hooksObserver) OnDone(info ExecInfo) {
+	switch {
+	case info.Attempt == 0 && info.Outcome == OutcomeCancelled:
+		if o.hooks.OnCancel != nil {
+			o.hooks.OnCancel(info.Err)
+		}
+	case info.Err != nil:
+		if o.hooks.OnCatch != nil {
+			o.hooks.OnCatch(info.Err)
+		}
+	}
+	if info.CatchPanic != nil && o.hooks.OnCatchPanic != nil {
+		o.hooks.OnCatchPanic(info.CatchPanic)
+	}
+	if o.hooks.OnFinally != nil {
+		o.hooks.OnFinally()
+	}
+}
+
+// observed 判断块是否有观察者
+func (tc *TryCatchBlock) observed() bool {
+	return tc.opts != nil && len(tc.opts.observers) > 0
+}
+
+// observeBegin 在 Do 开始时重置本次执行的信息
+func (tc *TryCatchBlock) observeBegin() {
+	tc.opts.info = ExecInfo{Name: tc.name, Context: tc.ctx}
+}
+
+// observeTryStart 记录一次尝试的开始并通知观察者
+func (tc *TryCatchBlock) observeTryStart(ctx context.Context, attempt int) {
+	now := time.Now()
+	if tc.opts.info.Start.IsZero() {
+		tc.opts.info.Start = now
+	}
+	tc.opts.info.Context = ctx
+	tc.opts.info.Attempt = attempt
+	tc.opts.info.End = time.Time{}
+	tc.opts.info.Err, tc.opts.info.PanicValue, tc.opts.info.Outcome = nil, nil, OutcomeSuccess
+	tc.opts.info.Abandoned = false
+	for _, o := range tc.opts.observers {
+		o.OnTryStart(tc.opts.info)
+	}
+}
+
+// observeTryEnd 记录一次尝试的结果并通知观察者
+func (tc *TryCatchBlock) observeTryEnd(err error, panicVal any) {
+	tc.recordTryEnd(err, panicVal)
+	for _, o := range tc.opts.observers {
+		o.OnTryEnd(tc.opts.info)
+	}
+}
+
+// recordTryEnd 记录一次尝试的结果
+func (tc *TryCatchBlock) recordTryEnd(err error, panicVal any) {
+	tc.opts.info.End = time.Now()
+	tc.opts.info.Err, tc.opts.info.PanicValue = err, panicVal
+	tc.opts.info.Outcome = outcomeOf(tc.opts.info.Context, err, panicVal)
+}
+
+// observeDone 记录本次 Do 的最终结果并通知观察者，返回观察者中 panic 转换得到的错误
+// 它在 Do 的 defer 中执行，每个回调都在隔离环境中调用，观察者的 panic 不会跳过 finally，也不会影响其他观察者。
+// try 发生 panic 时尝试尚未结束（End 为零值），先补发 OnTryEnd
+func (tc *TryCatchBlock) observeDone(err error, panicVal, catchPanic any, d *defaults, converter PanicConverter) (guardErr error) {
+	if !tc.opts.info.Start.IsZero() && tc.opts.info.End.IsZero() {
+		tc.recordTryEnd(err, panicVal)
+		for _, o := range tc.opts.observers {
+			guardErr = joinErrors(guardErr, tc.observerGuard(Observer.OnTryEnd, o, tc.opts.info, d, converter))
+		}
+	}
+	info := &tc.opts.info
+	info.Err, info.PanicValue, info.CatchPanic = err, panicVal, catchPanic
+	info.Outcome = outcomeOf(info.Context, err, panicVal)
+	for _, o := range tc.opts.observers {
+		guardErr = joinErrors(guardErr, tc.observerGuard(Observer.OnDone, o, *info, d, converter))
+	}
+	return guardErr
+}
+
+// observeFinally 在 finally 之后通知实现了 FinallyObserver 的观察者，返回观察者中 panic 转换得到的错误
+func (tc *TryCatchBlock) observeFinally(finallyErr error, d *defaults, converter PanicConverter) (guardErr error) {
+	info := tc.opts.info
+	info.FinallyErr = finallyErr
+	for _, o := range tc.opts.observers {
+		if _, ok := o.(FinallyObserver); ok {
+			guardErr = joinErrors(guardErr, tc.observerGuard(callOnFinally, o, info, d, converter))
+		}
+	}
+	return guardErr
+}
+
+// callOnFinally 调用观察者的 OnFinally
+func callOnFinally(o Observer, info ExecInfo) {
+	o.(FinallyObserver).OnFinally(info)
+}
+
+// observerGuard 在隔离环境中调用观察者的回调，将回调中的 panic 转换为错误返回
+func (tc *TryCatchBlock) observerGuard(call func(Observer, ExecInfo), o Observer, info ExecInfo, d *defaults, converter PanicConverter) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = convertPanic(r, tc.name, 1, d, converter)
+		}
+	}()
+	call(o, info)
+	return nil
+}
@@ -0,0 +1,305 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingObserver 按顺序记录收到的事件
+type recordingObserver struct {
+	events []string
+	infos  []ExecInfo
+}
+
+func (o *recordingObserver) record(event string, info ExecInfo) {
+	o.events = append(o.events, event)
+	o.infos = append(o.infos, info)
+}
+
+func (o *recordingObserver) OnTryStart(info ExecInfo) { o.record("start", info) }
+func (o *recordingObserver) OnTryEnd(info ExecInfo)   { o.record("end", info) }
+func (o *recordingObserver) OnDone(info ExecInfo)     { o.record("done", info) }
+
+func (o *recordingObserver) done() ExecInfo {
+	return o.infos[len(o.infos)-1]
+}
+
+func TestObserver_Success(t *testing.T) {
+	obs := &recordingObserver{}
+	ctx := context.WithValue(context.Background(), struct{}{}, "v")
+
+	err := NewWithOptions(WithName("op"), WithContext(ctx), WithObserver(obs)).
+		Try(func() error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"start", "end", "done"}, obs.events)
+	info := obs.done()
+	assert.Equal(t, "op", info.Name)
+	assert.Equal(t, ctx, info.Context)
+	assert.Equal(t, 1, info.Attempt)
+	assert.Equal(t, OutcomeSuccess, info.Outcome)
+	assert.GreaterOrEqual(t, info.Duration(), 5*time.Millisecond)
+}
+
+func TestObserver_Error(t *testing.T) {
+	obs := &recordingObserver{}
+	myErr := errors.New("boom")
+
+	err := NewWithOptions(WithObserver(obs)).
+		Try(func() error { return myErr }).
+		CatchErr(func(error) error { return nil }).
+		Do()
+
+	assert.NoError(t, err, "catch recovered the error")
+	info := obs.done()
+	assert.Equal(t, OutcomeError, info.Outcome)
+	assert.Equal(t, myErr, info.Err, "observer sees the error before catch")
+}
+
+func TestObserver_Panic(t *testing.T) {
+	obs := &recordingObserver{}
+
+	_ = NewWithOptions(WithObserver(obs)).
+		Try(func() error { panic("boom") }).
+		Do()
+
+	assert.Equal(t, []string{"start", "end", "done"}, obs.events)
+	for _, info := range obs.infos[1:] {
+		assert.Equal(t, OutcomePanic, info.Outcome)
+		assert.Equal(t, "boom", info.PanicValue)
+		assert.ErrorAs(t, info.Err, new(*PanicError))
+		assert.False(t, info.End.IsZero())
+	}
+}
+
+func TestObserver_CatchPanic(t *testing.T) {
+	obs := &recordingObserver{}
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithObserver(obs)).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	info := obs.done()
+	assert.Equal(t, OutcomeError, info.Outcome)
+	assert.Equal(t, "catch boom", info.CatchPanic)
+}
+
+func TestObserver_Cancelled(t *testing.T) {
+	obs := &recordingObserver{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = NewWithOptions(WithContext(ctx), WithObserver(obs)).
+		Try(func() error { return nil }).
+		Do()
+
+	assert.Equal(t, []string{"done"}, obs.events)
+	info := obs.done()
+	assert.Equal(t, OutcomeCancelled, info.Outcome)
+	assert.Equal(t, 0, info.Attempt)
+	assert.True(t, info.Start.IsZero())
+	assert.ErrorIs(t, info.Err, context.Canceled)
+}
+
+func TestObserver_Timeout(t *testing.T) {
+	obs := &recordingObserver{}
+
+	_ = NewWithOptions(WithTimeout(10*time.Millisecond), WithObserver(obs)).
+		TryCtx(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Do()
+
+	assert.Equal(t, OutcomeCancelled, obs.done().Outcome)
+	assert.False(t, obs.done().Abandoned, "a body that returns on its own is not abandoned")
+}
+
+func TestObserver_Abandoned(t *testing.T) {
+	obs := &recordingObserver{}
+	release := make(chan struct{})
+	defer close(release)
+
+	_ = NewWithOptions(WithTimeout(10*time.Millisecond), WithEnforceDeadline(), WithObserver(obs)).
+		Try(func() error {
+			<-release
+			return nil
+		}).
+		Do()
+
+	assert.Equal(t, []string{"start", "end", "done"}, obs.events)
+	assert.True(t, obs.infos[1].Abandoned)
+	assert.True(t, obs.done().Abandoned)
+	assert.Equal(t, OutcomeCancelled, obs.done().Outcome)
+}
+
+func TestObserver_Retry(t *testing.T) {
+	obs := &recordingObserver{}
+	attempts := 0
+
+	_ = NewWithOptions(WithObserver(obs), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		Retryable:   func(error) bool { return true },
+	})).
+		Try(func() error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("transient")
+			}
+			if attempts == 2 {
+				panic("flaky")
+			}
+			return nil
+		}).
+		Do()
+
+	assert.Equal(t, []string{"start", "end", "start", "end", "start", "end", "done"}, obs.events)
+	assert.Equal(t, OutcomeError, obs.infos[1].Outcome)
+	assert.Equal(t, OutcomePanic, obs.infos[3].Outcome)
+	info := obs.done()
+	assert.Equal(t, 3, info.Attempt)
+	assert.Equal(t, OutcomeSuccess, info.Outcome)
+	assert.Equal(t, obs.infos[0].Start, info.Start, "start is the first attempt")
+}
+
+func TestObserver_Repanic(t *testing.T) {
+	obs := &recordingObserver{}
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithObserver(obs), WithRepanicPolicy(func(any) bool { return true })).
+			Try(func() error { panic("fatal") }).
+			Do()
+	})
+
+	info := obs.done()
+	assert.Equal(t, OutcomePanic, info.Outcome)
+	assert.Equal(t, "fatal", info.PanicValue)
+}
+
+func TestObserver_MultipleAndReset(t *testing.T) {
+	a, b := &recordingObserver{}, &recordingObserver{}
+	tc := NewWithOptions(WithObserver(a), WithObserver(b)).Try(func() error { return nil })
+	_ = tc.Do()
+
+	assert.Len(t, a.events, 3)
+	assert.Len(t, b.events, 3)
+
+	tc.Reset()
+	_ = tc.Try(func() error { return nil }).Do()
+	assert.Len(t, a.events, 3, "observers should be cleared by Reset")
+}
+
+func TestHooksObserver(t *testing.T) {
+	var events []string
+	obs := HooksObserver(Hooks{
+		OnTryStart:   func() { events = append(events, "start") },
+		OnTryEnd:     func(error) { events = append(events, "end") },
+		OnCatch:      func(error) { events = append(events, "catch") },
+		OnCatchPanic: func(any) { events = append(events, "catch-panic") },
+		OnCancel:     func(error) { events = append(events, "cancel") },
+		OnFinally:    func() { events = append(events, "finally") },
+	})
+
+	_ = NewWithOptions(WithObserver(obs)).Try(func() error { return errors.New("boom") }).Do()
+	assert.Equal(t, []string{"start", "end", "catch", "finally"}, events)
+
+	events = nil
+	_ = NewWithOptions(WithObserver(obs)).Try(func() error { panic("boom") }).Do()
+	assert.Equal(t, []string{"start", "catch", "finally"}, events, "OnTryEnd is skipped on panic")
+
+	events = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = NewWithOptions(WithContext(ctx), WithObserver(obs)).Try(func() error { return nil }).Do()
+	assert.Equal(t, []string{"cancel", "finally"}, events)
+}
+
+func TestOutcome_String(t *testing.T) {
+	assert.Equal(t, "success", OutcomeSuccess.String())
+	assert.Equal(t, "error", OutcomeError.String())
+	assert.Equal(t, "panic", OutcomePanic.String())
+	assert.Equal(t, "cancelled", OutcomeCancelled.String())
+	assert.Equal(t, "unknown", Outcome(42).String())
+}
+
+// panickingObserver 在 OnDone 中发生 panic
+type panickingObserver struct{ recordingObserver }
+
+func (o *panickingObserver) OnDone(ExecInfo) { panic("observer boom") }
+
+func TestObserver_PanicInOnDone(t *testing.T) {
+	bad := &panickingObserver{}
+	after := &recordingObserver{}
+	finallyCount := 0
+
+	var err error
+	assert.NotPanics(t, func() {
+		err = NewWithOptions(WithObserver(bad, after)).
+			Try(func() error { return nil }).
+			Finally(func() { finallyCount++ }).
+			Do()
+	})
+
+	assert.Equal(t, 1, finallyCount, "finally should run exactly once")
+	var pe *PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, "observer boom", pe.Value)
+	assert.Equal(t, []string{"start", "end", "done"}, after.events, "later observers are still notified")
+}
+
+// finallyObserver 额外记录 OnFinally
+type finallyObserver struct{ recordingObserver }
+
+func (o *finallyObserver) OnFinally(info ExecInfo) { o.record("finally", info) }
+
+func TestObserver_OnFinally(t *testing.T) {
+	obs := &finallyObserver{}
+	myErr := errors.New("boom")
+	var order []string
+
+	err := NewWithOptions(WithObserver(obs), WithHooks(Hooks{OnFinally: func() { order = append(order, "hook") }})).
+		TryScope(func(scope *Scope) error {
+			scope.Defer(func() { panic("cleanup boom") })
+			return myErr
+		}).
+		FinallyErr(func() error {
+			order = append(order, "finally")
+			return errors.New("close failed")
+		}).
+		Do()
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"start", "end", "done", "finally"}, obs.events)
+	assert.Equal(t, []string{"hook", "finally"}, order)
+	info := obs.done()
+	assert.Equal(t, OutcomeError, info.Outcome)
+	assert.ErrorIs(t, info.Err, myErr)
+	assert.ErrorContains(t, info.FinallyErr, "cleanup boom")
+	assert.ErrorContains(t, info.FinallyErr, "close failed")
+	assert.NotErrorIs(t, info.FinallyErr, myErr)
+}
+
+func TestObserver_OnFinallyCatchPanic(t *testing.T) {
+	obs := &finallyObserver{}
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithObserver(obs)).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	assert.Equal(t, []string{"start", "end", "done", "finally"}, obs.events)
+	assert.Equal(t, "catch boom", obs.done().CatchPanic)
+}
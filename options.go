@@ -2,6 +2,7 @@ package gotrycatch
 
 import (
 	"context"
+	"time"
 )
 
 // Option 定义 TryCatchBlock 的配置选项
@@ -14,12 +15,94 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
+// WithTimeout 为 try 设置超时时间，超时后传给 TryCtx 的 context 会被取消
+// 默认只做协作式取消，需要在超时后立即返回时配合 WithEnforceDeadline 使用
+func WithTimeout(d time.Duration) Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().timeout = d
+	}
+}
+
+// WithEnforceDeadline 开启截止时间强制模式
+// try 在独立的 goroutine 中执行，context 结束（超时或取消）时 Do 立即返回 ctx.Err()，
+// 不再等待 try 完成；被放弃的 try 会在后台继续运行，并通过 OnAbandon 钩子通知
+func WithEnforceDeadline() Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().enforceDeadline = true
+	}
+}
+
 // Hooks 定义用于监控 TryCatchBlock 执行的回调
 type Hooks struct {
-	OnTryStart func()      // 在 try 执行前调用
-	OnTryEnd   func(error) // 在 try 执行后调用，传入错误结果
-	OnCatch    func(error) // 在 catch 执行时调用
-	OnFinally  func()      // 在 finally 执行时调用
+	OnTryStart   func()                       // 在 try 执行前调用
+	OnTryEnd     func(error)                  // 在 try 执行后调用，传入错误结果
+	OnCatch      func(error)                  // 在 catch 执行时调用
+	OnFinally    func()                       // 在 finally 执行时调用
+	OnAbandon    func(error)                  // 在强制截止模式下放弃仍在运行的 try 时调用，传入 ctx.Err()
+	OnRetry      func(attempt int, err error) // 在重试前调用，传入已失败的尝试次数（从 1 开始）及其错误
+	OnCatchPanic func(recovered any)          // 在 catch 自身发生 panic 时调用，传入 panic 值；随后 finally 执行，panic 继续向上传播
+	OnCancel     func(error)                  // 在 context 于 try 执行前已结束时调用，传入 ctx.Err()；此时 try 与 catch 都不会执行
+}
+
+// ChainHooks 将多组钩子合并为一组，每个回调按参数顺序依次调用，nil 回调被跳过
+// 用于在已有钩子之上叠加额外的监控（例如 tracing、metrics），而不覆盖调用方设置的钩子
+func ChainHooks(hooks ...Hooks) Hooks {
+	var chained Hooks
+	for _, h := range hooks {
+		chained.OnTryStart = chainFunc(chained.OnTryStart, h.OnTryStart)
+		chained.OnTryEnd = chainErrFunc(chained.OnTryEnd, h.OnTryEnd)
+		chained.OnCatch = chainErrFunc(chained.OnCatch, h.OnCatch)
+		chained.OnFinally = chainFunc(chained.OnFinally, h.OnFinally)
+		chained.OnAbandon = chainErrFunc(chained.OnAbandon, h.OnAbandon)
+		chained.OnCancel = chainErrFunc(chained.OnCancel, h.OnCancel)
+		if prev, next := chained.OnCatchPanic, h.OnCatchPanic; prev == nil {
+			chained.OnCatchPanic = next
+		} else if next != nil {
+			chained.OnCatchPanic = func(recovered any) {
+				prev(recovered)
+				next(recovered)
+			}
+		}
+		if prev, next := chained.OnRetry, h.OnRetry; prev == nil {
+			chained.OnRetry = next
+		} else if next != nil {
+			chained.OnRetry = func(attempt int, err error) {
+				prev(attempt, err)
+				next(attempt, err)
+			}
+		}
+	}
+	return chained
+}
+
+// chainFunc 依次调用 prev 与 next，任一方为 nil 时直接返回另一方
+func chainFunc(prev, next func()) func() {
+	switch {
+	case prev == nil:
+		return next
+	case next == nil:
+		return prev
+	default:
+		return func() {
+			prev()
+			next()
+		}
+	}
+}
+
+// chainErrFunc 依次调用 prev 与 next，任一方为 nil 时直接返回另一方
+func chainErrFunc(prev, next func(error)) func(error) {
+	switch {
+	case prev == nil:
+		return next
+	case next == nil:
+		return prev
+	default:
+		return func(err error) {
+			prev(err)
+			next(err)
+		}
+	}
 }
 
 // WithHooks 添加监控执行的钩子
@@ -51,6 +134,11 @@ func (tc *TryCatchBlock) Hooks() Hooks {
 	return tc.hooks
 }
 
+// RepanicPolicy 返回与 TryCatchBlock 关联的重新抛出策略，未设置时返回 nil
+func (tc *TryCatchBlock) RepanicPolicy() func(recovered any) bool {
+	return tc.options().repanic
+}
+
 // ApplyOptions 将提供的选项应用到 TryCatchBlock
 func (tc *TryCatchBlock) ApplyOptions(opts ...Option) *TryCatchBlock {
 	for _, opt := range opts {
@@ -286,4 +286,91 @@ func TestNewWithOptions_NoOptions(t *testing.T) {
 	assert.Nil(t, tc.ctx)
 	assert.Equal(t, "", tc.Name())
 	assert.Equal(t, Hooks{}, tc.Hooks())
+	assert.Nil(t, tc.RepanicPolicy())
+}
+
+func TestRepanicPolicy_Getter(t *testing.T) {
+	tc := NewWithOptions(WithRepanicPolicy(RepanicRuntimeErrors))
+
+	policy := tc.RepanicPolicy()
+	if assert.NotNil(t, policy) {
+		assert.False(t, policy("boom"))
+	}
+}
+
+func TestChainHooks(t *testing.T) {
+	var order []string
+	record := func(name string) Hooks {
+		return Hooks{
+			OnTryStart: func() { order = append(order, name+":start") },
+			OnTryEnd:   func(error) { order = append(order, name+":end") },
+			OnCatch:    func(error) { order = append(order, name+":catch") },
+			OnFinally:  func() { order = append(order, name+":finally") },
+		}
+	}
+
+	_ = NewWithOptions(WithHooks(ChainHooks(record("a"), Hooks{}, record("b")))).
+		Try(func() error { return errors.New("boom") }).
+		Catch(func(error) {}).
+		Do()
+
+	assert.Equal(t, []string{
+		"a:start", "b:start",
+		"a:end", "b:end",
+		"a:catch", "b:catch",
+		"a:finally", "b:finally",
+	}, order)
+}
+
+func TestChainHooks_Retry(t *testing.T) {
+	var attempts []int
+	hooks := ChainHooks(
+		Hooks{OnRetry: func(attempt int, _ error) { attempts = append(attempts, attempt) }},
+		Hooks{OnRetry: func(attempt int, _ error) { attempts = append(attempts, -attempt) }},
+	)
+
+	_ = NewWithOptions(WithHooks(hooks), WithRetry(RetryPolicy{MaxAttempts: 2})).
+		Try(func() error { return errors.New("boom") }).
+		Do()
+
+	assert.Equal(t, []int{1, -1}, attempts)
+}
+
+func TestChainHooks_Empty(t *testing.T) {
+	hooks := ChainHooks()
+	assert.Nil(t, hooks.OnTryStart)
+	assert.Nil(t, hooks.OnRetry)
+}
+
+func TestHooks_OnCatchPanic(t *testing.T) {
+	var recovered any
+	var finallyCalled bool
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithHooks(Hooks{
+			OnCatchPanic: func(r any) { recovered = r },
+			OnFinally:    func() { finallyCalled = true },
+		})).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	assert.Equal(t, "catch boom", recovered)
+	assert.True(t, finallyCalled)
+}
+
+func TestHooks_OnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var cancelErr error
+
+	err := NewWithOptions(WithContext(ctx), WithHooks(Hooks{
+		OnCancel: func(err error) { cancelErr = err },
+	})).
+		Try(func() error { return nil }).
+		Do()
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, cancelErr, context.Canceled)
 }
@@ -0,0 +1,26 @@
+module github.com/shengyanli1982/go-trycatch/oteltc
+
+go 1.25.0
+
+// 仓库内开发与 CI 使用本地的根模块；依赖方会忽略 replace，按下面 require 的版本解析，
+// 因此发布本模块之前需要先为根模块打上该版本的标签
+replace github.com/shengyanli1982/go-trycatch => ../
+
+require (
+	github.com/shengyanli1982/go-trycatch v0.2.0
+	github.com/stretchr/testify v1.12.1
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/sdk v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-logr/logr v1.4.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel/metric v1.46.0 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+)
@@ -0,0 +1,188 @@
+// Package oteltc 为 gotrycatch 提供 OpenTelemetry tracing 集成
+//
+// WithTracing 返回的选项会为每次 Do 开启一个 span，span 名称取自 WithName；
+// try 的错误或 panic（含调用栈）被记录为 span 事件并设置 span 状态，重试、放弃与 finally 记录为子事件
+package oteltc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 是创建 Tracer 时使用的 instrumentation 名称
+const instrumentationName = "github.com/shengyanli1982/go-trycatch/oteltc"
+
+// defaultSpanName 是块未命名时使用的 span 名称
+const defaultSpanName = "trycatch"
+
+// 记录到 span 上的属性键
+const (
+	attrName       = attribute.Key("trycatch.name")
+	attrPanic      = attribute.Key("trycatch.panic")
+	attrAttempt    = attribute.Key("trycatch.attempt")
+	attrFinally    = attribute.Key("trycatch.finally")
+	attrStacktrace = attribute.Key("exception.stacktrace")
+)
+
+// config 保存 tracing 的配置
+type config struct {
+	provider trace.TracerProvider // 创建 Tracer 的 TracerProvider
+	spanName string               // 块未命名时使用的 span 名称
+}
+
+// Option 定义 tracing 的配置选项
+type Option func(*config)
+
+// WithTracerProvider 指定创建 Tracer 的 TracerProvider，默认使用 otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithSpanName 指定块未通过 WithName 命名时使用的 span 名称，默认为 "trycatch"
+func WithSpanName(name string) Option {
+	return func(c *config) {
+		c.spanName = name
+	}
+}
+
+// WithTracing 返回为块开启 tracing 的选项
+// span 的开启、结果与结束都来自 gtc.Observer，span 在 finally 之后结束，不受块的钩子影响；
+// Scope 清理函数与 finally 的错误或 panic 记录在 finally 事件之后，同样使 span 状态为失败；
+// 自定义 PanicConverter 转换后的 panic 与按 WithRepanicPolicy 重新抛出的 panic 都会被记录为失败。
+// 重试与放弃事件同样来自 gtc.Observer，与 WithHooks 的应用顺序无关。
+// span 以块的 context 为父 context 开启，并通过中间件注入传给 try 的 context，
+// 因此 TryCtx 内部（例如 HTTP、数据库客户端）创建的 span 会成为它的子 span。先于 WithTracing 添加的中间件位于外层，看不到这个 span
+func WithTracing(opts ...Option) gtc.Option {
+	cfg := config{spanName: defaultSpanName}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.provider == nil {
+		cfg.provider = otel.GetTracerProvider()
+	}
+	tracer := cfg.provider.Tracer(instrumentationName)
+
+	return func(tc *gtc.TryCatchBlock) {
+		r := &recorder{tracer: tracer, spanName: cfg.spanName}
+		tc.ApplyOptions(gtc.WithObserver(r), gtc.WithMiddleware(r.inject))
+	}
+}
+
+// recorder 在一次 Do 的各个回调之间维护 span 状态
+// 与 TryCatchBlock 一样，每个块在同一时刻只应在一个 goroutine 中执行；
+// 只有 inject 可能在 WithEnforceDeadline 的独立 goroutine 中读取 span，由 mu 保护
+type recorder struct {
+	tracer   trace.Tracer
+	spanName string
+	mu       sync.Mutex
+	span     trace.Span // 当前 Do 的 span，尚未开启时为 nil
+	lastErr  error      // 上一次尝试的错误，重试时记录到 retry 事件中
+}
+
+// inject 是把当前 span 注入传给 try 的 context 的中间件
+func (r *recorder) inject(next func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		span := r.span
+		r.mu.Unlock()
+		if span != nil {
+			ctx = trace.ContextWithSpan(ctx, span)
+		}
+		return next(ctx)
+	}
+}
+
+// OnTryStart 开启 span；重试时会多次调用，只在第一次尝试时开启，之后的尝试记录为 retry 事件
+func (r *recorder) OnTryStart(info gtc.ExecInfo) {
+	if r.span != nil {
+		r.retry(info.Attempt - 1)
+		return
+	}
+	ctx := info.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	spanName := info.Name
+	if spanName == "" {
+		spanName = r.spanName
+	}
+	_, span := r.tracer.Start(ctx, spanName, trace.WithAttributes(attrName.String(info.Name)))
+	r.mu.Lock()
+	r.span = span
+	r.mu.Unlock()
+}
+
+// OnTryEnd 记录尝试的错误供重试事件使用，尝试被放弃时记录 abandon 事件；结果在 OnFinally 中统一记录
+func (r *recorder) OnTryEnd(info gtc.ExecInfo) {
+	r.lastErr = info.Err
+	if info.Abandoned && r.span != nil {
+		r.span.AddEvent("abandon", trace.WithAttributes(attribute.String("exception.message", info.Err.Error())))
+	}
+}
+
+// retry 将一次失败的尝试记录为 span 事件，attempt 为失败的尝试序号
+func (r *recorder) retry(attempt int) {
+	attrs := []attribute.KeyValue{attrAttempt.Int(attempt)}
+	if r.lastErr != nil {
+		attrs = append(attrs, attribute.String("exception.message", r.lastErr.Error()))
+	}
+	r.span.AddEvent("retry", trace.WithAttributes(attrs...))
+}
+
+// OnDone 不做任何事，结果在 OnFinally 中统一记录
+func (r *recorder) OnDone(gtc.ExecInfo) {}
+
+// OnFinally 记录 try 的结果与 finally 事件，设置 span 状态并结束 span
+// 每次 Do 都会在 finally 之后调用且不会被钩子覆盖，因此 span 总会结束，下一次 Do 总会开启新的 span；
+// Scope 清理函数与 finally 的错误（含 panic）同样被记录为失败
+func (r *recorder) OnFinally(info gtc.ExecInfo) {
+	r.mu.Lock()
+	span := r.span
+	r.span = nil
+	r.mu.Unlock()
+	r.lastErr = nil
+	// context 在执行前已结束时 try 没有执行，不产生 span
+	if span == nil {
+		return
+	}
+
+	var status error
+	if info.Outcome != gtc.OutcomeSuccess {
+		panicked := info.Outcome == gtc.OutcomePanic
+		err := info.Err
+		if err == nil {
+			// 按 WithRepanicPolicy 重新抛出的 panic 没有转换为错误
+			err = fmt.Errorf("panic: %v", info.PanicValue)
+		}
+		attrs := []attribute.KeyValue{attrPanic.Bool(panicked)}
+		var panicErr *gtc.PanicError
+		if panicked && errors.As(err, &panicErr) {
+			attrs = append(attrs, attrStacktrace.String(panicErr.StackTrace()))
+		}
+		span.RecordError(err, trace.WithAttributes(attrs...))
+		status = err
+	}
+
+	span.AddEvent("finally")
+	if info.FinallyErr != nil {
+		span.RecordError(info.FinallyErr, trace.WithAttributes(attrFinally.Bool(true)))
+		status = errors.Join(status, info.FinallyErr)
+	}
+
+	if status != nil {
+		span.SetStatus(codes.Error, status.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
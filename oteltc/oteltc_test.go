@@ -0,0 +1,328 @@
+package oteltc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func eventNames(span tracetest.SpanStub) []string {
+	names := make([]string, 0, len(span.Events))
+	for _, event := range span.Events {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+func attrValue(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestWithTracing_Success(t *testing.T) {
+	tp, exporter := newProvider()
+
+	err := gtc.NewWithOptions(gtc.WithName("load-user"), WithTracing(WithTracerProvider(tp))).
+		Try(func() error { return nil }).
+		Do()
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "load-user", spans[0].Name)
+	assert.Equal(t, codes.Ok, spans[0].Status.Code)
+	assert.Equal(t, []string{"finally"}, eventNames(spans[0]))
+}
+
+func TestWithTracing_Error(t *testing.T) {
+	tp, exporter := newProvider()
+	myErr := errors.New("boom")
+
+	_ = gtc.NewWithOptions(WithTracing(WithTracerProvider(tp))).
+		Try(func() error { return myErr }).
+		Do()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, defaultSpanName, spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "boom", spans[0].Status.Description)
+	assert.Equal(t, []string{"exception", "finally"}, eventNames(spans[0]))
+
+	panicked, ok := attrValue(spans[0].Events[0].Attributes, attrPanic)
+	assert.True(t, ok)
+	assert.False(t, panicked.AsBool())
+}
+
+func TestWithTracing_Panic(t *testing.T) {
+	tp, exporter := newProvider()
+
+	_ = gtc.NewWithOptions(WithTracing(WithTracerProvider(tp))).
+		Try(func() error { panic("kaboom") }).
+		Catch(func(error) {}).
+		Do()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+
+	event := spans[0].Events[0]
+	assert.Equal(t, "exception", event.Name)
+	panicked, _ := attrValue(event.Attributes, attrPanic)
+	assert.True(t, panicked.AsBool())
+	stack, ok := attrValue(event.Attributes, attrStacktrace)
+	assert.True(t, ok)
+	assert.Contains(t, stack.AsString(), "TestWithTracing_Panic")
+}
+
+func TestWithTracing_Retry(t *testing.T) {
+	tp, exporter := newProvider()
+	attempts := 0
+
+	err := gtc.NewWithOptions(
+		gtc.WithRetry(gtc.RetryPolicy{MaxAttempts: 3}),
+		WithTracing(WithTracerProvider(tp)),
+	).
+		Try(func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		}).
+		Do()
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1, "retries should share one span")
+	assert.Equal(t, []string{"retry", "retry", "finally"}, eventNames(spans[0]))
+	assert.Equal(t, codes.Ok, spans[0].Status.Code)
+}
+
+func TestWithTracing_FinallyError(t *testing.T) {
+	tp, exporter := newProvider()
+
+	err := gtc.NewWithOptions(WithTracing(WithTracerProvider(tp))).
+		TryScope(func(scope *gtc.Scope) error {
+			scope.Defer(func() { panic("cleanup boom") })
+			return nil
+		}).
+		FinallyErr(func() error { return errors.New("close failed") }).
+		Do()
+	assert.Error(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, []string{"finally", "exception"}, eventNames(spans[0]))
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "a failing finally should fail the span")
+	assert.Contains(t, spans[0].Status.Description, "cleanup boom")
+	assert.Contains(t, spans[0].Status.Description, "close failed")
+
+	finally, ok := attrValue(spans[0].Events[1].Attributes, attrFinally)
+	assert.True(t, ok)
+	assert.True(t, finally.AsBool())
+}
+
+func TestWithTracing_ParentContext(t *testing.T) {
+	tp, exporter := newProvider()
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	_ = gtc.NewWithOptions(gtc.WithContext(ctx), WithTracing(WithTracerProvider(tp))).
+		Try(func() error { return nil }).
+		Do()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestWithTracing_ChildSpans(t *testing.T) {
+	tp, exporter := newProvider()
+
+	err := gtc.NewWithOptions(gtc.WithName("load-user"), WithTracing(WithTracerProvider(tp))).
+		TryCtx(func(ctx context.Context) error {
+			_, child := tp.Tracer("client").Start(ctx, "db.query")
+			child.End()
+			return nil
+		}).
+		Do()
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "db.query", spans[0].Name)
+	assert.Equal(t, "load-user", spans[1].Name)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID(), "spans created in try should be children of the block span")
+}
+
+func TestWithTracing_KeepsExistingHooks(t *testing.T) {
+	tp, exporter := newProvider()
+	var finallyCalled bool
+
+	_ = gtc.NewWithOptions(
+		gtc.WithHooks(gtc.Hooks{OnFinally: func() { finallyCalled = true }}),
+		WithTracing(WithTracerProvider(tp)),
+	).
+		Try(func() error { return nil }).
+		Do()
+
+	assert.True(t, finallyCalled)
+	assert.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestWithTracing_PanickingFinallyHook(t *testing.T) {
+	tp, exporter := newProvider()
+	tc := gtc.NewWithOptions(
+		gtc.WithHooks(gtc.Hooks{OnFinally: func() { panic("hook boom") }}),
+		WithTracing(WithTracerProvider(tp)),
+	).Try(func() error { return nil })
+
+	assert.Error(t, tc.Do())
+	assert.Error(t, tc.Do())
+
+	assert.Len(t, exporter.GetSpans(), 2, "every Do should end its own span")
+}
+
+func TestWithTracing_HooksAppliedLater(t *testing.T) {
+	tp, exporter := newProvider()
+	attempts := 0
+
+	_ = gtc.NewWithOptions(
+		gtc.WithRetry(gtc.RetryPolicy{MaxAttempts: 2}),
+		WithTracing(WithTracerProvider(tp)),
+		gtc.WithHooks(gtc.Hooks{}),
+	).
+		Try(func() error {
+			attempts++
+			if attempts < 2 {
+				return errors.New("transient")
+			}
+			return nil
+		}).
+		Do()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1, "WithHooks should not drop the span end")
+	assert.Equal(t, []string{"retry", "finally"}, eventNames(spans[0]), "WithHooks should not drop the retry events")
+	attempt, _ := attrValue(spans[0].Events[0].Attributes, attrAttempt)
+	assert.Equal(t, int64(1), attempt.AsInt64())
+	message, _ := attrValue(spans[0].Events[0].Attributes, "exception.message")
+	assert.Equal(t, "transient", message.AsString())
+}
+
+func TestWithTracing_Abandon(t *testing.T) {
+	tp, exporter := newProvider()
+	release := make(chan struct{})
+	defer close(release)
+
+	err := gtc.NewWithOptions(
+		gtc.WithTimeout(10*time.Millisecond),
+		gtc.WithEnforceDeadline(),
+		WithTracing(WithTracerProvider(tp)),
+		gtc.WithHooks(gtc.Hooks{}),
+	).
+		Try(func() error {
+			<-release
+			return nil
+		}).
+		Do()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, []string{"abandon", "exception", "finally"}, eventNames(spans[0]))
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestWithTracing_CancelledBeforeExecution(t *testing.T) {
+	tp, exporter := newProvider()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := gtc.NewWithOptions(gtc.WithContext(ctx), WithTracing(WithTracerProvider(tp))).
+		Try(func() error { return nil }).
+		Do()
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, exporter.GetSpans())
+}
+
+func TestWithTracing_Reuse(t *testing.T) {
+	tp, exporter := newProvider()
+	tc := gtc.NewWithOptions(WithTracing(WithTracerProvider(tp))).
+		Try(func() error { return errors.New("boom") })
+
+	_ = tc.Do()
+	tc.Try(func() error { return nil })
+	_ = tc.Do()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, codes.Ok, spans[1].Status.Code)
+}
+
+func TestWithTracing_PanicConverter(t *testing.T) {
+	tp, exporter := newProvider()
+	converted := errors.New("converted")
+
+	err := gtc.NewWithOptions(
+		gtc.WithPanicConverter(gtc.PanicConverterFunc(func(any, []byte) error { return converted })),
+		WithTracing(WithTracerProvider(tp)),
+	).
+		Try(func() error { panic("kaboom") }).
+		Do()
+	assert.Same(t, converted, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "a converted panic should not be traced as success")
+	assert.Equal(t, "converted", spans[0].Status.Description)
+
+	event := spans[0].Events[0]
+	assert.Equal(t, "exception", event.Name)
+	panicked, _ := attrValue(event.Attributes, attrPanic)
+	assert.True(t, panicked.AsBool())
+}
+
+func TestWithTracing_Repanic(t *testing.T) {
+	tp, exporter := newProvider()
+
+	assert.Panics(t, func() {
+		_ = gtc.NewWithOptions(gtc.WithRepanicPolicy(gtc.RepanicRuntimeErrors), WithTracing(WithTracerProvider(tp))).
+			Try(func() error {
+				var m map[string]int
+				m["boom"] = 1
+				return nil
+			}).
+			Do()
+	})
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "a re-thrown panic should not be traced as success")
+	assert.Contains(t, spans[0].Status.Description, "assignment to entry in nil map")
+
+	event := spans[0].Events[0]
+	assert.Equal(t, "exception", event.Name)
+	panicked, _ := attrValue(event.Attributes, attrPanic)
+	assert.True(t, panicked.AsBool())
+}
@@ -0,0 +1,107 @@
+package gotrycatch
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// maxStackDepth 限制捕获调用栈的最大帧数
+const maxStackDepth = 64
+
+// PanicError 表示从 panic 中恢复的错误，保留原始 panic 值与调用栈
+type PanicError struct {
+	Value any       // 原始 panic 值
+	Name  string    // 发生 panic 的块名称
+	Time  time.Time // panic 被恢复的时间
+
+	pcs    []uintptr       // panic 发生时的程序计数器，首次访问 Stack 时才解析
+	once   sync.Once       // 保证 pcs 只解析一次
+	frames []runtime.Frame // 解析后的调用栈
+}
+
+// Error 返回 panic 值的字符串表示
+func (e *PanicError) Error() string {
+	switch v := e.Value.(type) {
+	case error:
+		return v.Error()
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Unwrap 在 panic 值本身是 error 时返回该值，以支持 errors.Is / errors.As
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// IsRuntimeError 判断 panic 值是否为 runtime.Error（如 nil 指针解引用、数组越界），这类 panic 通常意味着程序缺陷
+func (e *PanicError) IsRuntimeError() bool {
+	_, ok := e.Value.(runtime.Error)
+	return ok
+}
+
+// Stack 返回 panic 发生时的调用栈，栈帧在首次调用时才解析，并发调用是安全的
+func (e *PanicError) Stack() []runtime.Frame {
+	e.once.Do(func() {
+		e.frames = resolveFrames(e.pcs)
+	})
+	return e.frames
+}
+
+// StackTrace 以 "函数\n\t文件:行号" 的格式返回 panic 发生时的调用栈，便于写入日志或 tracing 属性
+func (e *PanicError) StackTrace() string {
+	return string(formatStack(e))
+}
+
+// newPanicError 将 recover() 得到的值转换为 *PanicError，并捕获当前调用栈
+// skip 为需要跳过的调用帧数（不含 newPanicError 自身）
+// 如果恢复值已经是 *PanicError（例如嵌套块向上传播），则原样返回以保留最初的调用栈；
+// 否则新建 *PanicError 并通知 d 中注册的 panic 处理函数，保证每个 panic 只上报一次
+func newPanicError(r any, name string, skip int, d *defaults) *PanicError {
+	if pe, ok := r.(*PanicError); ok {
+		return pe
+	}
+	pe := &PanicError{
+		Value: r,
+		Name:  name,
+		Time:  time.Now(),
+		pcs:   callers(skip + 1),
+	}
+	d.notifyPanic(pe)
+	return pe
+}
+
+// callers 返回调用方的程序计数器，skip 为需要跳过的调用帧数（不含 callers 自身）
+// 只复制原始 PC，符号解析推迟到 resolveFrames，使未读取调用栈的 panic 保持廉价
+func callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	if n == 0 {
+		return nil
+	}
+	return append([]uintptr(nil), pcs[:n]...)
+}
+
+// resolveFrames 将程序计数器解析为栈帧
+func resolveFrames(pcs []uintptr) []runtime.Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(pcs)
+	stack := make([]runtime.Frame, 0, len(pcs))
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+		if !more {
+			break
+		}
+	}
+	return stack
+}
@@ -0,0 +1,121 @@
+package gotrycatch
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicError_Error(t *testing.T) {
+	assert.Equal(t, "boom", (&PanicError{Value: "boom"}).Error())
+	assert.Equal(t, "wrapped", (&PanicError{Value: errors.New("wrapped")}).Error())
+	assert.Equal(t, "42", (&PanicError{Value: 42}).Error())
+}
+
+func TestPanicError_Unwrap(t *testing.T) {
+	myErr := errors.New("inner")
+	assert.Equal(t, myErr, (&PanicError{Value: myErr}).Unwrap())
+	assert.Nil(t, (&PanicError{Value: "not an error"}).Unwrap())
+}
+
+func TestPanicError_FromDo(t *testing.T) {
+	err := New().
+		ApplyOptions(WithName("panic-block")).
+		Try(func() error {
+			panic(42)
+		}).
+		Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, 42, panicErr.Value)
+	assert.Equal(t, "panic-block", panicErr.Name)
+	assert.False(t, panicErr.Time.IsZero(), "panic time should be recorded")
+	assert.NotEmpty(t, panicErr.Stack(), "panic stack should be captured")
+
+	found := false
+	for _, frame := range panicErr.Stack() {
+		if strings.Contains(frame.Function, "TestPanicError_FromDo") {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "stack should include the panicking function")
+}
+
+func TestPanicError_ReturnedErrorIsNotPanicError(t *testing.T) {
+	err := New().
+		Try(func() error {
+			return errors.New("plain error")
+		}).
+		Do()
+
+	var panicErr *PanicError
+	assert.False(t, errors.As(err, &panicErr), "returned error should not be reported as panic")
+}
+
+func TestPanicError_FromGenerics(t *testing.T) {
+	_, err := TryWithResult(func() (int, error) {
+		panic("generic panic")
+	})
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "generic panic", panicErr.Value)
+
+	_, err = TryCatchR(func() (int, error) {
+		panic("typed panic")
+	}, nil, nil)
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "typed panic", panicErr.Value)
+}
+
+func TestPanicError_NestedKeepsOriginal(t *testing.T) {
+	inner := &PanicError{Value: "inner", Name: "inner-block"}
+	err := New().
+		ApplyOptions(WithName("outer-block")).
+		Try(func() error {
+			panic(inner)
+		}).
+		Do()
+
+	assert.Same(t, inner, err, "re-panicked *PanicError should be returned as is")
+}
+
+func TestPanicError_StackTrace(t *testing.T) {
+	err := New().Try(func() error { panic("boom") }).Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	trace := panicErr.StackTrace()
+	assert.Contains(t, trace, "TestPanicError_StackTrace")
+	assert.Contains(t, trace, "panic_error_test.go:")
+	assert.Empty(t, (&PanicError{Value: "no stack"}).StackTrace())
+}
+
+func TestPanicError_StackResolvedOnce(t *testing.T) {
+	err := New().Try(func() error { panic("boom") }).Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+
+	var wg sync.WaitGroup
+	stacks := make([][]runtime.Frame, 8)
+	for i := range stacks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stacks[i] = panicErr.Stack()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, stack := range stacks {
+		assert.NotEmpty(t, stack)
+		assert.Same(t, &stacks[0][0], &stack[0], "frames should be resolved once and shared")
+	}
+}
@@ -0,0 +1,129 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrNoBranches 表示 TryAny 没有可执行的分支，因此没有分支成功
+var ErrNoBranches = errors.New("gotrycatch: no branches to run")
+
+// branchResult 保存一个分支的执行结果
+type branchResult[T any] struct {
+	index int   // 分支在 fns 中的下标
+	value T     // 分支的返回值
+	err   error // 分支的错误
+}
+
+// startBranches 为每个 fn 启动一个 goroutine，在各自的块中执行，结果通过带缓冲的 channel 返回，
+// 调用方提前返回时不会阻塞剩余的分支；ctx 会覆盖 opts 中通过 WithContext 设置的 context
+// 与 Go 一样，catch 中的 panic 转换为错误，命中 WithRepanicPolicy 策略的 panic 除外
+func startBranches[T any](ctx context.Context, fns []func(context.Context) (T, error), opts []Option) <-chan branchResult[T] {
+	results := make(chan branchResult[T], len(fns))
+	branchOpts := append(slices.Clip(opts), WithContext(ctx))
+	for i, fn := range fns {
+		i, fn := i, fn
+		go func() {
+			tb := NewTyped[T](branchOpts...).TryCtx(fn)
+			res := branchResult[T]{index: i}
+			run := func() (err error) {
+				res.value, err = tb.Do()
+				return err
+			}
+			tb.block.guardDo(run, func(err error) {
+				res.err = err
+				results <- res
+			})
+		}()
+	}
+	return results
+}
+
+// untyped 将不带返回值的分支包装为 TryAllR 等函数接受的形式
+func untyped(fns []func(context.Context) error) []func(context.Context) (struct{}, error) {
+	wrapped := make([]func(context.Context) (struct{}, error), len(fns))
+	for i, fn := range fns {
+		fn := fn
+		wrapped[i] = func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, fn(ctx)
+		}
+	}
+	return wrapped
+}
+
+// TryAll 并发执行所有 fn，每个 fn 在各自的块中执行（panic 被恢复为错误），等待全部结束后合并所有错误
+// opts 应用到每个分支，因此钩子、名称等对每个分支生效；ctx 会覆盖 opts 中通过 WithContext 设置的 context
+func TryAll(ctx context.Context, fns []func(context.Context) error, opts ...Option) error {
+	_, err := TryAllR(ctx, untyped(fns), opts...)
+	return err
+}
+
+// TryAllR 类似 TryAll，按 fns 的顺序返回每个分支的结果
+func TryAllR[T any](ctx context.Context, fns []func(context.Context) (T, error), opts ...Option) ([]T, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results := startBranches(ctx, fns, opts)
+	values := make([]T, len(fns))
+	errs := make([]error, len(fns))
+	for range fns {
+		res := <-results
+		values[res.index], errs[res.index] = res.value, res.err
+	}
+	return values, errors.Join(errs...)
+}
+
+// TryAny 并发执行所有 fn，返回第一个成功的分支并取消其余分支；全部失败时按 fns 的顺序合并所有错误
+// 成功后立即返回，不等待被取消的分支结束，它们的结果将被丢弃；fns 为空时返回 ErrNoBranches
+func TryAny(ctx context.Context, fns []func(context.Context) error, opts ...Option) error {
+	_, err := TryAnyR(ctx, untyped(fns), opts...)
+	return err
+}
+
+// TryAnyR 类似 TryAny，返回第一个成功的分支的结果
+func TryAnyR[T any](ctx context.Context, fns []func(context.Context) (T, error), opts ...Option) (T, error) {
+	var zero T
+	if len(fns) == 0 {
+		return zero, ErrNoBranches
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := startBranches(ctx, fns, opts)
+	errs := make([]error, len(fns))
+	for range fns {
+		res := <-results
+		if res.err == nil {
+			return res.value, nil
+		}
+		errs[res.index] = res.err
+	}
+	return zero, errors.Join(errs...)
+}
+
+// TryRace 并发执行所有 fn，返回第一个结束的分支的结果（无论成功或失败）并取消其余分支
+// 返回后不等待被取消的分支结束，它们的结果将被丢弃；fns 为空时返回 nil
+func TryRace(ctx context.Context, fns []func(context.Context) error, opts ...Option) error {
+	_, err := TryRaceR(ctx, untyped(fns), opts...)
+	return err
+}
+
+// TryRaceR 类似 TryRace，返回第一个结束的分支的结果
+func TryRaceR[T any](ctx context.Context, fns []func(context.Context) (T, error), opts ...Option) (T, error) {
+	var zero T
+	if len(fns) == 0 {
+		return zero, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	res := <-startBranches(ctx, fns, opts)
+	return res.value, res.err
+}
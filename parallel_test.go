@@ -0,0 +1,177 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockUntilDone 返回一个阻塞到 ctx 结束的分支，开始执行时关闭 started，结束时记录
+func blockUntilDone(started chan struct{}, cancelled *atomic.Int32) func(context.Context) error {
+	return func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Add(1)
+		return ctx.Err()
+	}
+}
+
+func TestTryAll_JoinsErrors(t *testing.T) {
+	err1, err2 := errors.New("one"), errors.New("two")
+	var ran atomic.Int32
+
+	err := TryAll(context.Background(), []func(context.Context) error{
+		func(context.Context) error { ran.Add(1); return err1 },
+		func(context.Context) error { ran.Add(1); return nil },
+		func(context.Context) error { ran.Add(1); panic("boom") },
+		func(context.Context) error { ran.Add(1); return err2 },
+	})
+
+	assert.Equal(t, int32(4), ran.Load(), "TryAll waits for every branch")
+	assert.ErrorIs(t, err, err1)
+	assert.ErrorIs(t, err, err2)
+	assert.ErrorAs(t, err, new(*PanicError))
+}
+
+func TestTryAll_Success(t *testing.T) {
+	assert.NoError(t, TryAll(context.Background(), []func(context.Context) error{
+		func(context.Context) error { return nil },
+		func(context.Context) error { return nil },
+	}))
+	assert.NoError(t, TryAll(context.Background(), nil))
+}
+
+func TestTryAllR_KeepsOrder(t *testing.T) {
+	values, err := TryAllR(context.Background(), []func(context.Context) (int, error){
+		func(context.Context) (int, error) { time.Sleep(10 * time.Millisecond); return 1, nil },
+		func(context.Context) (int, error) { return 2, nil },
+		func(context.Context) (int, error) { return 3, errors.New("boom") },
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, values)
+	assert.EqualError(t, err, "boom")
+}
+
+func TestTryAny_FirstSuccessCancelsRest(t *testing.T) {
+	var cancelled atomic.Int32
+	started := make(chan struct{})
+
+	v, err := TryAnyR(context.Background(), []func(context.Context) (string, error){
+		func(context.Context) (string, error) { return "", errors.New("fast failure") },
+		func(ctx context.Context) (string, error) { return "", blockUntilDone(started, &cancelled)(ctx) },
+		func(context.Context) (string, error) { <-started; return "winner", nil },
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "winner", v)
+	waitFor(t, func() bool { return cancelled.Load() == 1 })
+}
+
+func TestTryAny_AllFail(t *testing.T) {
+	err1, err2 := errors.New("one"), errors.New("two")
+
+	err := TryAny(context.Background(), []func(context.Context) error{
+		func(context.Context) error { return err1 },
+		func(context.Context) error { panic(err2) },
+	})
+
+	assert.ErrorIs(t, err, err1)
+	assert.ErrorIs(t, err, err2)
+}
+
+func TestTryAny_NoBranches(t *testing.T) {
+	assert.ErrorIs(t, TryAny(context.Background(), nil), ErrNoBranches)
+
+	v, err := TryAnyR(context.Background(), []func(context.Context) (int, error){})
+	assert.ErrorIs(t, err, ErrNoBranches, "zero branches should not look like a success")
+	assert.Zero(t, v)
+}
+
+func TestTryRace_FirstCompletion(t *testing.T) {
+	var cancelled atomic.Int32
+	started := make(chan struct{})
+	errFast := errors.New("fast")
+
+	err := TryRace(context.Background(), []func(context.Context) error{
+		blockUntilDone(started, &cancelled),
+		func(context.Context) error { <-started; return errFast },
+	})
+
+	assert.Equal(t, errFast, err, "race returns the first completion even when it fails")
+	waitFor(t, func() bool { return cancelled.Load() == 1 })
+}
+
+func TestTryRaceR_Value(t *testing.T) {
+	v, err := TryRaceR(context.Background(), []func(context.Context) (int, error){
+		func(ctx context.Context) (int, error) { <-ctx.Done(); return 0, ctx.Err() },
+		func(context.Context) (int, error) { return 7, nil },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, v)
+
+	v, err = TryRaceR[int](context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Zero(t, v)
+}
+
+func TestParallel_OptionsApplyToEachBranch(t *testing.T) {
+	var starts, catches atomic.Int32
+	var names []string
+	obs := &recordingObserver{}
+
+	_ = TryAll(context.Background(), []func(context.Context) error{
+		func(context.Context) error { return errors.New("boom") },
+		func(context.Context) error { return nil },
+	},
+		WithName("fanout"),
+		WithHooks(Hooks{
+			OnTryStart: func() { starts.Add(1) },
+			OnCatch:    func(error) { catches.Add(1) },
+		}),
+	)
+	assert.Equal(t, int32(2), starts.Load())
+	assert.Equal(t, int32(0), catches.Load(), "no catch handler is set on branches")
+
+	_ = TryAll(context.Background(), []func(context.Context) error{
+		func(context.Context) error { return nil },
+	}, WithName("single"), WithObserver(obs))
+	for _, info := range obs.infos {
+		names = append(names, info.Name)
+	}
+	assert.Equal(t, []string{"single", "single", "single"}, names)
+}
+
+func TestParallel_ContextOverridesOption(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "param")
+
+	err := TryAll(ctx, []func(context.Context) error{
+		func(ctx context.Context) error {
+			assert.Equal(t, "param", ctx.Value(key{}))
+			return nil
+		},
+	}, WithContext(context.WithValue(context.Background(), key{}, "option")))
+	assert.NoError(t, err)
+}
+
+func TestParallel_CatchPanicBecomesError(t *testing.T) {
+	err := TryAll(context.Background(), []func(context.Context) error{
+		func(context.Context) error { return errors.New("boom") },
+	}, func(tc *TryCatchBlock) { tc.Catch(func(error) { panic("catch boom") }) })
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "catch boom", panicErr.Value)
+}
+
+func TestParallel_Goexit(t *testing.T) {
+	err := TryAll(context.Background(), []func(context.Context) error{
+		func(context.Context) error { runtime.Goexit(); return nil },
+	})
+	assert.ErrorIs(t, err, ErrGoexit)
+}
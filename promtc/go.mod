@@ -0,0 +1,28 @@
+module github.com/shengyanli1982/go-trycatch/promtc
+
+go 1.25.0
+
+// 仓库内开发与 CI 使用本地的根模块；依赖方会忽略 replace，按下面 require 的版本解析，
+// 因此发布本模块之前需要先为根模块打上该版本的标签
+replace github.com/shengyanli1982/go-trycatch => ../
+
+require (
+	github.com/shengyanli1982/go-trycatch v0.2.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_golang v1.24.1
+	github.com/prometheus/client_model v0.6.2
+	github.com/prometheus/common v0.70.1 // indirect
+	github.com/prometheus/procfs v0.21.1 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
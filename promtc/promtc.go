@@ -0,0 +1,144 @@
+// Package promtc 为 gotrycatch 块提供 Prometheus 指标采集
+//
+// Collector 同时实现 gtc.Metrics 与 prometheus.Collector：通过 gtc.WithMetrics 接入块，
+// 再注册到任意 prometheus.Registerer。所有指标以 "name" 标签区分块名称
+package promtc
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gtc "github.com/shengyanli1982/go-trycatch"
+)
+
+// 确保 Collector 同时实现了 gtc.Metrics 与 prometheus.Collector
+var (
+	_ gtc.Metrics          = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// labelName 是区分块名称的标签
+const labelName = "name"
+
+// config 保存 Collector 的配置
+type config struct {
+	namespace   string
+	subsystem   string
+	constLabels prometheus.Labels
+	buckets     []float64
+}
+
+// Option 定义 Collector 的配置选项
+type Option func(*config)
+
+// WithNamespace 指定指标的 namespace，默认为 "trycatch"
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithSubsystem 指定指标的 subsystem，默认为空
+func WithSubsystem(subsystem string) Option {
+	return func(c *config) {
+		c.subsystem = subsystem
+	}
+}
+
+// WithConstLabels 为所有指标添加固定标签
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// WithBuckets 指定耗时直方图的桶上界（秒），默认为 prometheus.DefBuckets
+func WithBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// Collector 采集块的执行指标，可以被多个块并发共享
+type Collector struct {
+	executions    *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+	panics        *prometheus.CounterVec
+	catchPanics   *prometheus.CounterVec
+	cancellations *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+}
+
+// NewCollector 创建 Collector，需要调用方注册到 prometheus.Registerer
+func NewCollector(opts ...Option) *Collector {
+	cfg := config{namespace: "trycatch", buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	counter := func(name, help string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: cfg.constLabels,
+		}, []string{labelName})
+	}
+
+	return &Collector{
+		executions:    counter("executions_total", "Number of TryCatchBlock executions."),
+		errors:        counter("errors_total", "Number of executions whose try returned an error."),
+		panics:        counter("panics_total", "Number of executions whose try panicked."),
+		catchPanics:   counter("catch_panics_total", "Number of executions whose catch panicked."),
+		cancellations: counter("cancellations_total", "Number of executions cancelled by their context."),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        "try_duration_seconds",
+			Help:        "Duration of the try body, from the first attempt start to the last attempt end.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     cfg.buckets,
+		}, []string{labelName}),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.executions.Describe(ch)
+	c.errors.Describe(ch)
+	c.panics.Describe(ch)
+	c.catchPanics.Describe(ch)
+	c.cancellations.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.executions.Collect(ch)
+	c.errors.Collect(ch)
+	c.panics.Collect(ch)
+	c.catchPanics.Collect(ch)
+	c.cancellations.Collect(ch)
+	c.duration.Collect(ch)
+}
+
+// IncExecutions 增加 Do 的执行次数
+func (c *Collector) IncExecutions(name string) { c.executions.WithLabelValues(name).Inc() }
+
+// IncErrors 增加 try 返回错误的次数
+func (c *Collector) IncErrors(name string) { c.errors.WithLabelValues(name).Inc() }
+
+// IncPanics 增加 try 发生 panic 的次数
+func (c *Collector) IncPanics(name string) { c.panics.WithLabelValues(name).Inc() }
+
+// IncCatchPanics 增加 catch 自身发生 panic 的次数
+func (c *Collector) IncCatchPanics(name string) { c.catchPanics.WithLabelValues(name).Inc() }
+
+// IncCancellations 增加 context 取消的次数
+func (c *Collector) IncCancellations(name string) { c.cancellations.WithLabelValues(name).Inc() }
+
+// ObserveDuration 记录 try 的耗时
+func (c *Collector) ObserveDuration(name string, d time.Duration) {
+	c.duration.WithLabelValues(name).Observe(d.Seconds())
+}
@@ -0,0 +1,77 @@
+package promtc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector_Do(t *testing.T) {
+	c := NewCollector()
+	reg := prometheus.NewPedanticRegistry()
+	assert.NoError(t, reg.Register(c))
+
+	opts := []gtc.Option{gtc.WithName("op"), gtc.WithMetrics(c)}
+	_ = gtc.NewWithOptions(opts...).Try(func() error { return nil }).Do()
+	_ = gtc.NewWithOptions(opts...).Try(func() error { return errors.New("boom") }).Do()
+	_ = gtc.NewWithOptions(opts...).Try(func() error { panic("boom") }).Do()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = gtc.NewWithOptions(append(opts, gtc.WithContext(ctx))...).Try(func() error { return nil }).Do()
+
+	assert.Equal(t, 4.0, testutil.ToFloat64(c.executions.WithLabelValues("op")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.errors.WithLabelValues("op")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.panics.WithLabelValues("op")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.cancellations.WithLabelValues("op")))
+	var m dto.Metric
+	assert.NoError(t, c.duration.WithLabelValues("op").(prometheus.Histogram).Write(&m))
+	assert.Equal(t, uint64(3), m.GetHistogram().GetSampleCount(), "cancelled execution has no duration")
+}
+
+func TestCollector_CatchPanic(t *testing.T) {
+	c := NewCollector()
+
+	assert.Panics(t, func() {
+		_ = gtc.NewWithOptions(gtc.WithName("op"), gtc.WithMetrics(c)).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.catchPanics.WithLabelValues("op")))
+}
+
+func TestCollector_Options(t *testing.T) {
+	c := NewCollector(
+		WithNamespace("app"),
+		WithSubsystem("jobs"),
+		WithConstLabels(prometheus.Labels{"service": "api"}),
+		WithBuckets(0.1, 1),
+	)
+	c.IncExecutions("op")
+
+	expected := `
+# HELP app_jobs_executions_total Number of TryCatchBlock executions.
+# TYPE app_jobs_executions_total counter
+app_jobs_executions_total{name="op",service="api"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "app_jobs_executions_total"))
+}
+
+func TestCollector_Lint(t *testing.T) {
+	c := NewCollector()
+	c.IncExecutions("op")
+	c.ObserveDuration("op", 0)
+
+	problems, err := testutil.CollectAndLint(c)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
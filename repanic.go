@@ -0,0 +1,41 @@
+package gotrycatch
+
+import (
+	"runtime"
+)
+
+// WithRepanicPolicy 设置重新抛出 panic 的策略
+// policy 对 try 中恢复的原始 panic 值返回 true 时，该 panic 不会被转换为错误：
+// catch 与 OnCatch 不会执行，finally 执行完毕后以原始值重新 panic。
+// 适用于让 nil 指针、越界等代表程序缺陷的 runtime.Error 继续崩溃，同时恢复业务层面的 panic
+func WithRepanicPolicy(policy func(recovered any) bool) Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().repanic = policy
+	}
+}
+
+// RepanicRuntimeErrors 是对 runtime.Error 返回 true 的重新抛出策略，可直接传给 WithRepanicPolicy
+func RepanicRuntimeErrors(recovered any) bool {
+	_, ok := recovered.(runtime.Error)
+	return ok
+}
+
+// shouldRepanic 判断恢复值 r 是否需要按策略重新抛出，由 Throw 抛出的错误不会被重新抛出
+func (tc *TryCatchBlock) shouldRepanic(r any) bool {
+	policy := tc.options().repanic
+	if policy == nil {
+		return false
+	}
+	if _, ok := thrown(r); ok {
+		return false
+	}
+	return policy(panicValue(r))
+}
+
+// panicValue 返回原始的 panic 值；r 为内部传递的 *PanicError 时取其 Value
+func panicValue(r any) any {
+	if pe, ok := r.(*PanicError); ok {
+		return pe.Value
+	}
+	return r
+}
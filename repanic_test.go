@@ -0,0 +1,133 @@
+package gotrycatch
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepanicRuntimeErrors(t *testing.T) {
+	var runtimeErr any
+	func() {
+		defer func() { runtimeErr = recover() }()
+		var m map[string]int
+		m["x"] = 1
+	}()
+
+	assert.True(t, RepanicRuntimeErrors(runtimeErr))
+	assert.False(t, RepanicRuntimeErrors("business panic"))
+	assert.False(t, RepanicRuntimeErrors(errors.New("plain error")))
+}
+
+func TestPanicError_IsRuntimeError(t *testing.T) {
+	err := New().
+		Try(func() error {
+			var s []int
+			_ = s[3]
+			return nil
+		}).
+		Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.True(t, panicErr.IsRuntimeError())
+	assert.False(t, (&PanicError{Value: "business"}).IsRuntimeError())
+}
+
+func TestWithRepanicPolicy_RuntimeErrorRepanics(t *testing.T) {
+	catchCalled, onCatchCalled, finallyCalled := false, false, false
+
+	tc := NewWithOptions(
+		WithRepanicPolicy(RepanicRuntimeErrors),
+		WithHooks(Hooks{OnCatch: func(error) { onCatchCalled = true }}),
+	).
+		Try(func() error {
+			var p *struct{ v int }
+			_ = p.v
+			return nil
+		}).
+		Catch(func(error) {
+			catchCalled = true
+		}).
+		Finally(func() {
+			finallyCalled = true
+		})
+
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		tc.Do()
+	}()
+
+	_, isRuntimeErr := recovered.(runtime.Error)
+	assert.True(t, isRuntimeErr, "the original runtime.Error should be re-panicked")
+	assert.True(t, finallyCalled, "finally should run before re-panicking")
+	assert.False(t, catchCalled, "catch should not handle re-panicked values")
+	assert.False(t, onCatchCalled, "OnCatch should not run for re-panicked values")
+}
+
+func TestWithRepanicPolicy_BusinessPanicRecovered(t *testing.T) {
+	var err error
+
+	assert.NotPanics(t, func() {
+		err = NewWithOptions(WithRepanicPolicy(RepanicRuntimeErrors)).
+			Try(func() error {
+				panic("business panic")
+			}).
+			Do()
+	})
+
+	assert.EqualError(t, err, "business panic")
+}
+
+func TestWithRepanicPolicy_CustomPredicate(t *testing.T) {
+	type fatal struct{ reason string }
+
+	tc := NewWithOptions(WithRepanicPolicy(func(v any) bool {
+		_, ok := v.(fatal)
+		return ok
+	})).
+		Try(func() error {
+			panic(fatal{reason: "corrupted state"})
+		})
+
+	assert.PanicsWithValue(t, fatal{reason: "corrupted state"}, func() { tc.Do() })
+}
+
+func TestWithRepanicPolicy_AfterRetry(t *testing.T) {
+	attempts := 0
+
+	tc := NewWithOptions(
+		WithRepanicPolicy(func(v any) bool { return v == "fatal" }),
+		WithRetry(RetryPolicy{MaxAttempts: 3}),
+	).
+		Try(func() error {
+			attempts++
+			panic("fatal")
+		})
+
+	assert.PanicsWithValue(t, "fatal", func() { tc.Do() }, "policy should see the original value after retry")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWithRepanicPolicy_HandlersNotNotified(t *testing.T) {
+	resetDefaults(t)
+	reported := false
+	RegisterPanicHandler(func(*PanicError) { reported = true })
+
+	tc := NewWithOptions(WithRepanicPolicy(func(any) bool { return true })).
+		Try(func() error { panic("fatal") })
+
+	assert.Panics(t, func() { tc.Do() })
+	assert.False(t, reported, "re-panicked values are not recovered and should not be reported")
+}
+
+func TestWithRepanicPolicy_Reset(t *testing.T) {
+	tc := NewWithOptions(WithRepanicPolicy(RepanicRuntimeErrors))
+
+	tc.Reset()
+
+	assert.Nil(t, tc.options().repanic, "repanic policy should be cleared after Reset")
+}
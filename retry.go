@@ -0,0 +1,152 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff 根据已失败的尝试次数（从 1 开始）返回下一次重试前的等待时间
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff 返回固定等待时间的退避策略
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff 返回指数退避策略，等待时间为 base * 2^(attempt-1)，且不超过 max
+// max 小于等于 0 表示不设上限（等待时间最多为 math.MaxInt64 纳秒）；base 小于等于 0 表示不等待
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	limit := max
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+	return func(attempt int) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+		d := min(base, limit)
+		for i := 1; i < attempt && d < limit; i++ {
+			// 翻倍会超过上限（或溢出）时截断
+			if d > limit/2 {
+				return limit
+			}
+			d *= 2
+		}
+		return d
+	}
+}
+
+// JitterBackoff 为退避策略增加随机抖动（full jitter），实际等待时间在 [0, b(attempt)) 之间均匀分布
+func JitterBackoff(b Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		d := b(attempt)
+		if d <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(d)))
+	}
+}
+
+// RetryPolicy 定义 try 失败后的重试策略
+type RetryPolicy struct {
+	MaxAttempts int              // 最大尝试次数（含首次执行），小于等于 1 表示不重试
+	Backoff     Backoff          // 重试前的等待策略，nil 表示立即重试
+	Retryable   func(error) bool // 判断错误是否可重试，panic 以 *PanicError 传入；nil 表示除 panic 以外的错误均可重试
+}
+
+// WithRetry 为 TryCatchBlock 添加重试策略
+// try 失败时按策略重新执行，catch 与 finally 只在最后一次尝试之后执行一次
+func WithRetry(policy RetryPolicy) Option {
+	return func(tc *TryCatchBlock) {
+		tc.mutableOptions().retry = policy
+	}
+}
+
+// retryable 判断 err 是否可以重试，panicked 表示 err 由 panic 转换而来
+func (p *RetryPolicy) retryable(err error, panicked bool) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return !panicked
+}
+
+// delay 返回第 attempt 次尝试失败后的等待时间
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+	return p.Backoff(attempt)
+}
+
+// attempt 执行一次 try，并将 try 中的 panic 转换为 *PanicError 返回
+func (tc *TryCatchBlock) attempt(ctx context.Context, d *defaults, adm *admission, n int) (err error, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			// 命中重新抛出策略的 panic 不参与重试，立即交给 Do 按重新抛出处理
+			if tc.shouldRepanic(r) {
+				panic(r)
+			}
+			// 由 Throw 抛出的错误按普通错误处理，可以参与重试
+			if thrownErr, ok := thrown(r); ok {
+				err = thrownErr
+				tc.hookTryEnd(d, err)
+				return
+			}
+			pe := newPanicError(r, tc.name, 1, d)
+			err, panicked = pe, true
+			if tc.observed() {
+				tc.observeTryEnd(pe, pe.Value)
+			}
+		}
+	}()
+
+	tc.hookTryStart(d, ctx, n)
+	err = tc.runTry(ctx, d, adm)
+	tc.hookTryEnd(d, err)
+	return err, false
+}
+
+// tryWithRetry 按重试策略执行 try，返回最后一次尝试的错误
+// 最后一次尝试发生 panic 时重新抛出，由 Do 按 panic 路径统一处理；尝试结束时 ctx 已结束则不再重试；
+// 等待重试期间 ctx 结束时停止重试，返回最后一次的错误与 ctx.Err() 的合并结果
+func (tc *TryCatchBlock) tryWithRetry(ctx context.Context, d *defaults, adm *admission) error {
+	policy := &tc.opts.retry
+	for attempt := 1; ; attempt++ {
+		err, panicked := tc.attempt(ctx, d, adm, attempt)
+		if err == nil {
+			return nil
+		}
+		// ctx 已结束（超时或强制截止模式下被放弃）时不会再有下一次尝试，不调用 OnRetry，原样返回本次尝试的错误
+		if attempt >= policy.MaxAttempts || !policy.retryable(err, panicked) || ctx.Err() != nil {
+			if panicked {
+				panic(err)
+			}
+			return err
+		}
+
+		tc.hookRetry(d, attempt, err)
+		if waitErr := sleepContext(ctx, policy.delay(attempt)); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+	}
+}
+
+// sleepContext 等待 d 时长，ctx 提前结束时返回 ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,264 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	b := ConstantBackoff(10 * time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, b(1))
+	assert.Equal(t, 10*time.Millisecond, b(5))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, b(1))
+	assert.Equal(t, 20*time.Millisecond, b(2))
+	assert.Equal(t, 40*time.Millisecond, b(3))
+	assert.Equal(t, 50*time.Millisecond, b(4), "backoff should be capped at max")
+	assert.Equal(t, 50*time.Millisecond, b(100), "backoff should not overflow")
+
+	unbounded := ExponentialBackoff(time.Millisecond, 0)
+	assert.Equal(t, 8*time.Millisecond, unbounded(4))
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded(60), "an uncapped backoff should clamp instead of overflowing to 0")
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded(1000))
+
+	assert.Equal(t, 50*time.Millisecond, ExponentialBackoff(time.Second, 50*time.Millisecond)(1), "base above max is capped")
+	assert.Equal(t, time.Duration(0), ExponentialBackoff(0, time.Second)(5), "a non-positive base means no wait")
+	assert.Equal(t, time.Duration(0), ExponentialBackoff(-time.Millisecond, 0)(60))
+}
+
+func TestJitterBackoff(t *testing.T) {
+	b := JitterBackoff(ConstantBackoff(10 * time.Millisecond))
+	for i := 0; i < 100; i++ {
+		d := b(1)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, 10*time.Millisecond)
+	}
+	assert.Equal(t, time.Duration(0), JitterBackoff(ConstantBackoff(0))(1))
+}
+
+func TestWithRetry_SucceedsAfterFailures(t *testing.T) {
+	attempts := 0
+	var retried []int
+	catchCalled := false
+
+	err := New().
+		ApplyOptions(
+			WithRetry(RetryPolicy{MaxAttempts: 3}),
+			WithHooks(Hooks{
+				OnRetry: func(attempt int, err error) { retried = append(retried, attempt) },
+			}),
+		).
+		Try(func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		}).
+		Catch(func(err error) {
+			catchCalled = true
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []int{1, 2}, retried, "OnRetry should report each failed attempt")
+	assert.False(t, catchCalled, "catch should not run when a retry succeeds")
+}
+
+func TestWithRetry_CatchAndFinallyOnce(t *testing.T) {
+	attempts, catchCount, finallyCount, tryStartCount := 0, 0, 0, 0
+	lastErr := errors.New("attempt 3")
+
+	err := New().
+		ApplyOptions(
+			WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)}),
+			WithHooks(Hooks{
+				OnTryStart: func() { tryStartCount++ },
+			}),
+		).
+		Try(func() error {
+			attempts++
+			if attempts == 3 {
+				return lastErr
+			}
+			return errors.New("transient")
+		}).
+		Catch(func(err error) {
+			catchCount++
+		}).
+		Finally(func() {
+			finallyCount++
+		}).
+		Do()
+
+	assert.Equal(t, lastErr, err, "Do should return the error of the final attempt")
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, tryStartCount, "OnTryStart should run for every attempt")
+	assert.Equal(t, 1, catchCount, "catch should run once after the final attempt")
+	assert.Equal(t, 1, finallyCount, "finally should run once after the final attempt")
+}
+
+func TestWithRetry_PanicNotRetriedByDefault(t *testing.T) {
+	attempts := 0
+	var caughtErr error
+
+	err := New().
+		ApplyOptions(WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() error {
+			attempts++
+			panic("boom")
+		}).
+		Catch(func(err error) {
+			caughtErr = err
+		}).
+		Do()
+
+	assert.Equal(t, 1, attempts, "panics should not be retried by default")
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, err, caughtErr)
+}
+
+func TestWithRetry_RetryablePredicate(t *testing.T) {
+	permanent := errors.New("permanent")
+	attempts := 0
+
+	err := New().
+		ApplyOptions(WithRetry(RetryPolicy{
+			MaxAttempts: 5,
+			Retryable:   func(err error) bool { return !errors.Is(err, permanent) },
+		})).
+		Try(func() error {
+			attempts++
+			if attempts == 2 {
+				return permanent
+			}
+			return errors.New("transient")
+		}).
+		Do()
+
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 2, attempts, "non-retryable error should stop retrying")
+}
+
+func TestWithRetry_RetryPanicsWhenAllowed(t *testing.T) {
+	attempts := 0
+
+	err := New().
+		ApplyOptions(WithRetry(RetryPolicy{
+			MaxAttempts: 3,
+			Retryable:   func(error) bool { return true },
+		})).
+		Try(func() error {
+			attempts++
+			if attempts < 3 {
+				panic("flaky")
+			}
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestWithRetry_ContextCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	tryErr := errors.New("transient")
+	attempts := 0
+
+	start := time.Now()
+	err := New().
+		ApplyOptions(
+			WithContext(ctx),
+			WithRetry(RetryPolicy{MaxAttempts: 10, Backoff: ConstantBackoff(time.Second)}),
+		).
+		TryCtx(func(ctx context.Context) error {
+			attempts++
+			return tryErr
+		}).
+		Do()
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "backoff should be interrupted by context")
+	assert.Equal(t, 1, attempts)
+	assert.ErrorIs(t, err, tryErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithRetry_ContextDoneAfterAttempt(t *testing.T) {
+	retries := 0
+	attempts := 0
+
+	err := New().
+		ApplyOptions(
+			WithTimeout(10*time.Millisecond),
+			WithRetry(RetryPolicy{MaxAttempts: 3}),
+			WithHooks(Hooks{OnRetry: func(int, error) { retries++ }}),
+		).
+		TryCtx(func(ctx context.Context) error {
+			attempts++
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Do()
+
+	assert.Equal(t, 1, attempts)
+	assert.Zero(t, retries, "no retry should be reported when the context is already done")
+	assert.Equal(t, context.DeadlineExceeded, err, "the attempt error should be returned unchanged")
+}
+
+func TestWithRetry_SingleAttempt(t *testing.T) {
+	attempts := 0
+
+	New().
+		ApplyOptions(WithRetry(RetryPolicy{MaxAttempts: 1})).
+		Try(func() error {
+			attempts++
+			return errors.New("error")
+		}).
+		Do()
+
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWithRetry_Reset(t *testing.T) {
+	tc := NewWithOptions(WithRetry(RetryPolicy{MaxAttempts: 3}))
+
+	tc.Reset()
+
+	assert.Equal(t, 0, tc.options().retry.MaxAttempts, "retry policy should be cleared after Reset")
+}
+
+func TestWithRetry_RepanicNotRetried(t *testing.T) {
+	attempts := 0
+	finallyCalled := false
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(
+			WithRepanicPolicy(RepanicRuntimeErrors),
+			WithRetry(RetryPolicy{MaxAttempts: 3, Retryable: func(error) bool { return true }}),
+		).
+			Try(func() error {
+				attempts++
+				var m map[string]int
+				m["boom"] = 1
+				return nil
+			}).
+			Finally(func() { finallyCalled = true }).
+			Do()
+	})
+
+	assert.Equal(t, 1, attempts, "a panic matched by the repanic policy should not be retried")
+	assert.True(t, finallyCalled)
+}
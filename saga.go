@@ -0,0 +1,173 @@
+package gotrycatch
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Step 是 Saga 中的一个步骤
+type Step struct {
+	Name       string                          // 步骤名称，同时作为执行该步骤的块的名称
+	Do         func(ctx context.Context) error // 步骤的操作
+	Compensate func(ctx context.Context) error // 撤销已完成的操作，nil 表示无需补偿
+}
+
+// Saga 按顺序执行一组步骤；某个步骤失败（返回错误或发生 panic）时，按相反顺序补偿已完成的步骤
+// Saga 构建完成后可以被多次、并发地 Run
+type Saga struct {
+	steps []Step
+	opts  []Option
+}
+
+// NewSaga 创建由 steps 组成的 Saga
+func NewSaga(steps ...Step) *Saga {
+	return &Saga{steps: steps}
+}
+
+// Add 在末尾追加步骤
+func (s *Saga) Add(steps ...Step) *Saga {
+	s.steps = append(s.steps, steps...)
+	return s
+}
+
+// ApplyOptions 设置执行每个步骤与补偿时使用的选项，例如钩子、观察者、重试
+// 块的名称与 context 由 Saga 设置，opts 中的 WithName / WithContext 不生效
+func (s *Saga) ApplyOptions(opts ...Option) *Saga {
+	s.opts = append(s.opts, opts...)
+	return s
+}
+
+// Run 按顺序执行所有步骤，全部成功时返回 nil
+// 某个步骤失败时不再执行后续步骤，按相反顺序执行已完成步骤的补偿，并返回 *SagaError。
+// 补偿在隔离环境中逐个执行：一个补偿失败或 panic 不会阻止其余补偿；
+// 补偿使用 context.WithoutCancel(ctx)，即使 ctx 已被取消（例如步骤因超时失败）也会执行。
+// 步骤或补偿中命中 WithRepanicPolicy 的 panic 会先被暂存，全部补偿执行完毕后再以原始值重新抛出，
+// 步骤的 panic 优先于补偿的 panic
+func (s *Saga) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for i, step := range s.steps {
+		err, panicVal := s.exec(ctx, step.Name, step.Do)
+		if err == nil && panicVal == nil {
+			continue
+		}
+		compensations, compPanic := s.compensate(context.WithoutCancel(ctx), i)
+		if panicVal == nil {
+			panicVal = compPanic
+		}
+		if panicVal != nil {
+			panic(panicVal)
+		}
+		return &SagaError{
+			Step:          step.Name,
+			Index:         i,
+			Err:           err,
+			Compensations: compensations,
+		}
+	}
+	return nil
+}
+
+// compensate 按相反顺序补偿 failed 之前已完成的步骤，返回失败的补偿与第一个需要重新抛出的 panic
+func (s *Saga) compensate(ctx context.Context, failed int) (errs []*CompensationError, panicVal any) {
+	for i := failed - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		err, r := s.exec(ctx, step.Name, step.Compensate)
+		if r != nil && panicVal == nil {
+			panicVal = r
+		}
+		if err != nil {
+			errs = append(errs, &CompensationError{Step: step.Name, Index: i, Err: err})
+		}
+	}
+	return errs, panicVal
+}
+
+// exec 在块中执行 fn；块按重新抛出策略传播出来的 panic 不会中断 Saga，而是通过 panicVal 返回
+func (s *Saga) exec(ctx context.Context, name string, fn func(context.Context) error) (err error, panicVal any) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicVal = r
+		}
+	}()
+	return s.block(ctx, name).TryCtx(fn).Do(), nil
+}
+
+// block 创建执行步骤或补偿的块
+func (s *Saga) block(ctx context.Context, name string) *TryCatchBlock {
+	return NewWithOptions(s.opts...).ApplyOptions(WithName(name), WithContext(ctx))
+}
+
+// SagaError 描述 Saga 的失败：失败的步骤及其错误，以及执行失败的补偿
+type SagaError struct {
+	Step          string               // 失败步骤的名称
+	Index         int                  // 失败步骤的下标
+	Err           error                // 失败步骤的错误，panic 时为转换得到的错误
+	Compensations []*CompensationError // 执行失败的补偿，按执行顺序（即步骤的相反顺序）排列
+}
+
+// Error 返回失败步骤与补偿失败的描述
+func (e *SagaError) Error() string {
+	var b strings.Builder
+	b.WriteString("saga step ")
+	writeStepName(&b, e.Step, e.Index)
+	b.WriteString(" failed: ")
+	b.WriteString(e.Err.Error())
+	for _, c := range e.Compensations {
+		b.WriteString("; ")
+		b.WriteString(c.Error())
+	}
+	return b.String()
+}
+
+// Unwrap 返回失败步骤的错误与所有补偿错误，以支持 errors.Is / errors.As
+func (e *SagaError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Compensations)+1)
+	errs = append(errs, e.Err)
+	for _, c := range e.Compensations {
+		errs = append(errs, c)
+	}
+	return errs
+}
+
+// Compensated 报告所有已完成步骤的补偿是否都执行成功
+func (e *SagaError) Compensated() bool {
+	return len(e.Compensations) == 0
+}
+
+// CompensationError 描述一个执行失败的补偿
+type CompensationError struct {
+	Step  string // 步骤名称
+	Index int    // 步骤下标
+	Err   error  // 补偿的错误，panic 时为转换得到的错误
+}
+
+// Error 返回补偿失败的描述
+func (e *CompensationError) Error() string {
+	var b strings.Builder
+	b.WriteString("compensation of step ")
+	writeStepName(&b, e.Step, e.Index)
+	b.WriteString(" failed: ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap 返回补偿的错误
+func (e *CompensationError) Unwrap() error {
+	return e.Err
+}
+
+// writeStepName 写入步骤名称，未命名时使用下标
+func writeStepName(b *strings.Builder, name string, index int) {
+	if name == "" {
+		b.WriteString("#")
+		b.WriteString(strconv.Itoa(index))
+		return
+	}
+	b.WriteString(strconv.Quote(name))
+}
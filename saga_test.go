@@ -0,0 +1,243 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordStep 返回记录执行顺序的步骤
+func recordStep(name string, order *[]string, err error) Step {
+	return Step{
+		Name: name,
+		Do: func(context.Context) error {
+			*order = append(*order, "do "+name)
+			return err
+		},
+		Compensate: func(context.Context) error {
+			*order = append(*order, "undo "+name)
+			return nil
+		},
+	}
+}
+
+func TestSaga_Success(t *testing.T) {
+	var order []string
+
+	err := NewSaga(
+		recordStep("a", &order, nil),
+		recordStep("b", &order, nil),
+	).Add(recordStep("c", &order, nil)).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"do a", "do b", "do c"}, order, "no compensation should run on success")
+}
+
+func TestSaga_CompensatesInReverse(t *testing.T) {
+	var order []string
+	stepErr := errors.New("step error")
+
+	err := NewSaga(
+		recordStep("a", &order, nil),
+		recordStep("b", &order, nil),
+		recordStep("c", &order, stepErr),
+		recordStep("d", &order, nil),
+	).Run(context.Background())
+
+	assert.Equal(t, []string{"do a", "do b", "do c", "undo b", "undo a"}, order,
+		"completed steps should be compensated in reverse, the failed step should not")
+
+	var se *SagaError
+	assert.ErrorAs(t, err, &se)
+	assert.Equal(t, "c", se.Step)
+	assert.Equal(t, 2, se.Index)
+	assert.Same(t, stepErr, se.Err)
+	assert.True(t, se.Compensated())
+	assert.ErrorIs(t, err, stepErr)
+	assert.Equal(t, `saga step "c" failed: step error`, err.Error())
+}
+
+func TestSaga_StepPanic(t *testing.T) {
+	resetDefaults(t)
+	var order []string
+
+	err := NewSaga(
+		recordStep("a", &order, nil),
+		Step{Name: "b", Do: func(context.Context) error { panic("boom") }},
+	).Run(context.Background())
+
+	assert.Equal(t, []string{"do a", "undo a"}, order)
+
+	var pe *PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.Equal(t, "b", pe.Name, "the step block should be named after the step")
+}
+
+func TestSaga_CompensationFailures(t *testing.T) {
+	resetDefaults(t)
+	var order []string
+	compErr := errors.New("compensation error")
+
+	err := NewSaga(
+		recordStep("a", &order, nil),
+		Step{
+			Name:       "b",
+			Do:         func(context.Context) error { return nil },
+			Compensate: func(context.Context) error { panic("undo panic") },
+		},
+		Step{
+			Name:       "c",
+			Do:         func(context.Context) error { return nil },
+			Compensate: func(context.Context) error { return compErr },
+		},
+		Step{Name: "d"},
+		recordStep("e", &order, errors.New("step error")),
+	).Run(context.Background())
+
+	assert.Equal(t, []string{"do a", "do e", "undo a"}, order,
+		"a failing compensation should not prevent the others from running")
+
+	var se *SagaError
+	assert.ErrorAs(t, err, &se)
+	assert.False(t, se.Compensated())
+	if assert.Len(t, se.Compensations, 2) {
+		assert.Equal(t, "c", se.Compensations[0].Step)
+		assert.Equal(t, 2, se.Compensations[0].Index)
+		assert.Same(t, compErr, se.Compensations[0].Err)
+		assert.Equal(t, "b", se.Compensations[1].Step)
+		var pe *PanicError
+		assert.ErrorAs(t, se.Compensations[1].Err, &pe)
+		assert.Equal(t, "undo panic", pe.Value)
+	}
+	assert.ErrorIs(t, err, compErr)
+	assert.Equal(t, `saga step "e" failed: step error; `+
+		`compensation of step "c" failed: compensation error; `+
+		`compensation of step "b" failed: undo panic`, err.Error())
+}
+
+func TestSaga_CompensationIgnoresCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var compCtxErr error
+	compensated := false
+
+	err := NewSaga(
+		Step{
+			Do: func(context.Context) error { return nil },
+			Compensate: func(ctx context.Context) error {
+				compensated = true
+				compCtxErr = ctx.Err()
+				return nil
+			},
+		},
+		Step{Do: func(context.Context) error {
+			cancel()
+			return context.Cause(ctx)
+		}},
+		Step{Do: func(context.Context) error { return nil }},
+	).Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, compensated, "compensation should run even if ctx is cancelled")
+	assert.NoError(t, compCtxErr, "compensation should not observe the cancellation")
+	assert.Equal(t, `saga step #1 failed: context canceled`, err.Error())
+}
+
+func TestSaga_CancelledBeforeStep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var order []string
+
+	err := NewSaga(
+		Step{
+			Name: "a",
+			Do: func(context.Context) error {
+				cancel()
+				return nil
+			},
+			Compensate: func(context.Context) error {
+				order = append(order, "undo a")
+				return nil
+			},
+		},
+		recordStep("b", &order, nil),
+	).Run(ctx)
+
+	var se *SagaError
+	assert.ErrorAs(t, err, &se)
+	assert.Equal(t, "b", se.Step)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"undo a"}, order, "a step should not start after ctx is cancelled")
+}
+
+func TestSaga_ApplyOptions(t *testing.T) {
+	resetDefaults(t)
+	obs := &recordingObserver{}
+	attempts := 0
+
+	err := NewSaga(
+		Step{Name: "flaky", Do: func(context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return errors.New("transient")
+			}
+			return nil
+		}},
+	).ApplyOptions(WithRetry(RetryPolicy{MaxAttempts: 3}), WithObserver(obs), WithName("ignored")).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{"start", "end", "start", "end", "done"}, obs.events)
+	assert.Equal(t, "flaky", obs.infos[len(obs.infos)-1].Name, "the step name should override WithName")
+}
+
+func TestSaga_RepanicStillCompensates(t *testing.T) {
+	var order []string
+
+	assert.PanicsWithError(t, "assignment to entry in nil map", func() {
+		_ = NewSaga(
+			recordStep("a", &order, nil),
+			Step{
+				Name: "b",
+				Do: func(context.Context) error {
+					order = append(order, "do b")
+					return nil
+				},
+				Compensate: func(context.Context) error {
+					var m map[string]int
+					m["undo b"] = 1
+					return nil
+				},
+			},
+			recordStep("c", &order, nil),
+			Step{Name: "d", Do: func(context.Context) error {
+				var m map[string]int
+				m["d"] = 1
+				return nil
+			}},
+			recordStep("e", &order, nil),
+		).ApplyOptions(WithRepanicPolicy(RepanicRuntimeErrors)).Run(context.Background())
+	})
+
+	assert.Equal(t, []string{"do a", "do b", "do c", "undo c", "undo a"}, order,
+		"completed steps should be compensated before the panic is re-thrown, even if a compensation panics too")
+}
+
+func TestSaga_RepanicInCompensation(t *testing.T) {
+	var order []string
+
+	assert.PanicsWithValue(t, "undo bug", func() {
+		_ = NewSaga(
+			recordStep("a", &order, nil),
+			Step{
+				Name:       "b",
+				Do:         func(context.Context) error { return nil },
+				Compensate: func(context.Context) error { panic("undo bug") },
+			},
+			recordStep("c", &order, errors.New("step error")),
+		).ApplyOptions(WithRepanicPolicy(func(v any) bool { return v == "undo bug" })).Run(context.Background())
+	})
+
+	assert.Equal(t, []string{"do a", "do c", "undo a"}, order, "a panicking compensation should not stop the others")
+}
@@ -0,0 +1,175 @@
+package gotrycatch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Scope 是一次 Do 执行期间的清理函数栈
+// try 在获取资源后通过 Defer / DeferErr 注册清理函数，Do 在 catch 之后、finally 之前按后进先出的顺序执行它们。
+// 清理函数相互隔离，其中的 panic 被转换为错误，清理函数的错误通过 errors.Join 合并到 Do 的返回值中。
+// 配置重试时，所有尝试注册的清理函数都在最后一次尝试结束后统一执行。Scope 可并发使用
+type Scope struct {
+	mu       sync.Mutex
+	cleanups []cleanup // 按注册顺序保存的清理函数
+	closed   bool      // 清理函数是否已经执行
+	name     string    // 所属块的名称
+	d        *defaults // 所属块使用的进程级配置
+}
+
+// cleanup 是一个清理函数，fn 与 fnErr 只设置其一
+type cleanup struct {
+	fn    func()
+	fnErr func() error
+}
+
+// Defer 注册清理函数
+// 在清理函数已经执行之后注册（例如被 WithEnforceDeadline 放弃的 try 继续运行）时，fn 会被立即执行
+func (s *Scope) Defer(fn func()) {
+	if fn != nil {
+		s.push(cleanup{fn: fn})
+	}
+}
+
+// DeferErr 注册可返回错误的清理函数，适用于 Close() 等清理操作
+// 在清理函数已经执行之后注册时，fn 会被立即执行，其错误被丢弃
+func (s *Scope) DeferErr(fn func() error) {
+	if fn != nil {
+		s.push(cleanup{fnErr: fn})
+	}
+}
+
+// push 注册清理函数，Scope 已关闭时立即执行
+func (s *Scope) push(c cleanup) {
+	s.mu.Lock()
+	if !s.closed {
+		s.cleanups = append(s.cleanups, c)
+		s.mu.Unlock()
+		return
+	}
+	name, d := s.name, s.d
+	s.mu.Unlock()
+	_ = finallyGuard(c.fn, c.fnErr, name, d, nil)
+}
+
+// open 为新的一次执行重新打开 Scope
+func (s *Scope) open(name string, d *defaults) {
+	s.mu.Lock()
+	s.closed = false
+	s.name, s.d = name, d
+	s.mu.Unlock()
+}
+
+// close 关闭 Scope 并按后进先出的顺序执行清理函数，返回合并后的错误
+// 清理函数执行期间注册的新清理函数会被立即执行
+func (s *Scope) close(converter PanicConverter) (err error) {
+	// 取出清理函数的同时释放对闭包的引用，try 保留的 Scope 不会让清理函数捕获的资源无法回收
+	s.mu.Lock()
+	s.closed = true
+	cleanups := s.cleanups
+	s.cleanups = nil
+	s.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		err = joinErrors(err, finallyGuard(cleanups[i].fn, cleanups[i].fnErr, s.name, s.d, converter))
+	}
+	return err
+}
+
+// scopeKey 是 Scope 在 context 中的键
+type scopeKey struct{}
+
+// scopeContext 是携带 Scope 的 context，传给 try 与中间件
+type scopeContext struct {
+	context.Context
+	frame *scopeFrame
+}
+
+// Value 返回 Scope 并将其标记为已取出，其他键交给内层 context
+func (c *scopeContext) Value(key any) any {
+	if key == (scopeKey{}) {
+		c.frame.claimed.Store(true)
+		return &c.frame.scope
+	}
+	return c.Context.Value(key)
+}
+
+// ScopeFrom 返回 Do 传给 try 的 context 中携带的 Scope，嵌套执行时返回最内层块的 Scope
+// ctx 不是（也不派生自）Do 传给 try 的 context 时返回 nil
+// 应在 try 返回之前调用：块复用 context 时，Do 返回后对保留的 context 调用可能得到该块后续执行的 Scope
+func ScopeFrom(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// TryScope 设置接收 Scope 的 try 函数，等价于在 TryCtx 中调用 ScopeFrom
+// 与 TryCtx 共用同一个位置，后设置的生效；中间件需要基于收到的 context 派生新的 context，Scope 才能传递到 try
+func (tc *TryCatchBlock) TryScope(try func(*Scope) error) *TryCatchBlock {
+	if try == nil {
+		tc.tryCtx = nil
+		return tc
+	}
+	tc.tryCtx = func(ctx context.Context) error {
+		return try(ScopeFrom(ctx))
+	}
+	return tc
+}
+
+// scopeFrame 保存一次执行的 Scope 与携带它的 context
+// 传给 try 的 context 交给用户代码后不再被改写，try 启动的 goroutine 在 Do 返回（甚至块被 Reset）后仍可安全地使用它。
+// 父 context 为 context.Background() 时 scopeFrame 由块缓存并在后续执行中复用，此时改写的只有其中的 Scope；
+// Scope 被取出（ScopeFrom 或 TryScope）后可能被保留，缓存随之作废，下次执行重新分配
+type scopeFrame struct {
+	scope   Scope
+	ctx     scopeContext
+	claimed atomic.Bool // Scope 是否已被取出
+}
+
+// newScopeFrame 返回父 context 为 ctx 的 scopeFrame
+func newScopeFrame(ctx context.Context) *scopeFrame {
+	f := &scopeFrame{}
+	f.ctx = scopeContext{Context: ctx, frame: f}
+	return f
+}
+
+// openScope 为本次执行准备 Scope，ctx 为传给 try 的 context
+// 只有 try 能够拿到 context（TryCtx 或中间件）时才需要 Scope
+func (tc *TryCatchBlock) openScope(ctx context.Context, d *defaults) {
+	if tc.tryCtx != nil || tc.opts != nil && len(tc.opts.middlewares) > 0 {
+		tc.prepareScope(ctx, d)
+	}
+}
+
+// prepareScope 取得本次执行使用的 scopeFrame 并打开其中的 Scope
+func (tc *TryCatchBlock) prepareScope(ctx context.Context, d *defaults) {
+	var f *scopeFrame
+	if ctx == context.Background() {
+		if tc.spare == nil || tc.spare.claimed.Load() {
+			tc.spare = newScopeFrame(ctx)
+		}
+		f = tc.spare
+	} else {
+		f = newScopeFrame(ctx)
+	}
+	f.scope.open(tc.name, d)
+	tc.scope = f
+}
+
+// closeScope 执行本次执行注册的清理函数，未准备 Scope 时返回 nil
+func (tc *TryCatchBlock) closeScope(converter PanicConverter) error {
+	f := tc.scope
+	if f == nil {
+		return nil
+	}
+	tc.scope = nil
+	return f.scope.close(converter)
+}
+
+// tryContext 返回传给 try 的 context，准备了 Scope 时为携带 Scope 的 context
+func (tc *TryCatchBlock) tryContext(ctx context.Context) context.Context {
+	if tc.scope != nil {
+		return &tc.scope.ctx
+	}
+	return ctx
+}
@@ -0,0 +1,267 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScope_LIFOBeforeFinally(t *testing.T) {
+	var order []string
+	tryErr := errors.New("try error")
+
+	err := New().
+		TryCtx(func(ctx context.Context) error {
+			scope := ScopeFrom(ctx)
+			scope.Defer(func() { order = append(order, "cleanup 1") })
+			scope.DeferErr(func() error {
+				order = append(order, "cleanup 2")
+				return nil
+			})
+			order = append(order, "try")
+			return tryErr
+		}).
+		Catch(func(error) { order = append(order, "catch") }).
+		Finally(func() { order = append(order, "finally") }).
+		Do()
+
+	assert.Same(t, tryErr, err)
+	assert.Equal(t, []string{"try", "catch", "cleanup 2", "cleanup 1", "finally"}, order)
+}
+
+func TestScope_ErrorsJoined(t *testing.T) {
+	resetDefaults(t)
+	closeErr := errors.New("close error")
+	var ran []string
+
+	err := NewWithOptions(WithName("scoped")).
+		TryScope(func(scope *Scope) error {
+			scope.Defer(func() { ran = append(ran, "first") })
+			scope.DeferErr(func() error { return closeErr })
+			scope.Defer(func() { panic("cleanup panic") })
+			return nil
+		}).
+		Do()
+
+	assert.Equal(t, []string{"first"}, ran, "a failing cleanup should not prevent the others from running")
+	assert.ErrorIs(t, err, closeErr)
+	var pe *PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, "cleanup panic", pe.Value)
+	assert.Equal(t, "scoped", pe.Name)
+}
+
+func TestScope_TryPanic(t *testing.T) {
+	closed := false
+
+	err := New().
+		TryScope(func(scope *Scope) error {
+			scope.Defer(func() { closed = true })
+			panic("boom")
+		}).
+		Do()
+
+	assert.True(t, closed, "cleanups should run when try panics")
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestScope_Repanic(t *testing.T) {
+	closed := false
+
+	assert.Panics(t, func() {
+		_ = NewWithOptions(WithRepanicPolicy(func(any) bool { return true })).
+			TryScope(func(scope *Scope) error {
+				scope.Defer(func() { closed = true })
+				panic("bug")
+			}).
+			Do()
+	})
+	assert.True(t, closed, "cleanups should run before a panic is re-thrown")
+}
+
+func TestScope_Retry(t *testing.T) {
+	var order []string
+	attempts := 0
+
+	err := NewWithOptions(WithRetry(RetryPolicy{MaxAttempts: 3})).
+		TryScope(func(scope *Scope) error {
+			attempts++
+			n := attempts
+			scope.Defer(func() { order = append(order, "cleanup "+strconv.Itoa(n)) })
+			order = append(order, "attempt "+strconv.Itoa(n))
+			if n < 2 {
+				return errors.New("transient")
+			}
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"attempt 1", "attempt 2", "cleanup 2", "cleanup 1"}, order,
+		"cleanups of every attempt should run once after the last attempt")
+}
+
+func TestScope_DeferAfterClose(t *testing.T) {
+	var scope *Scope
+	err := New().
+		TryScope(func(s *Scope) error {
+			scope = s
+			return nil
+		}).
+		Do()
+	assert.NoError(t, err)
+
+	ran := false
+	scope.Defer(func() { ran = true })
+	assert.True(t, ran, "a cleanup registered after Do should run immediately")
+	assert.NotPanics(t, func() {
+		scope.DeferErr(func() error { panic("late panic") })
+	})
+}
+
+func TestScope_EnforceDeadline(t *testing.T) {
+	release := make(chan struct{})
+	var closed atomic.Bool
+
+	err := NewWithOptions(WithTimeout(10*time.Millisecond), WithEnforceDeadline()).
+		TryScope(func(scope *Scope) error {
+			<-release
+			scope.Defer(func() { closed.Store(true) })
+			return nil
+		}).
+		Do()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	waitFor(t, closed.Load)
+}
+
+func TestScope_Middleware(t *testing.T) {
+	var order []string
+
+	err := NewWithOptions(WithMiddleware(func(next func(context.Context) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			ScopeFrom(ctx).Defer(func() { order = append(order, "middleware cleanup") })
+			return next(ctx)
+		}
+	})).
+		Try(func() error {
+			order = append(order, "try")
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"try", "middleware cleanup"}, order)
+}
+
+func TestScope_Nested(t *testing.T) {
+	var order []string
+
+	err := New().
+		TryScope(func(outer *Scope) error {
+			outer.Defer(func() { order = append(order, "outer") })
+			return New().
+				TryCtx(func(ctx context.Context) error {
+					assert.NotSame(t, outer, ScopeFrom(ctx), "the innermost scope should be returned")
+					ScopeFrom(ctx).Defer(func() { order = append(order, "inner") })
+					return nil
+				}).
+				Do()
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"inner", "outer"}, order)
+}
+
+func TestScope_Reuse(t *testing.T) {
+	tc := New()
+	count := 0
+	try := func(scope *Scope) error {
+		scope.Defer(func() { count++ })
+		return nil
+	}
+
+	assert.NoError(t, tc.TryScope(try).Do())
+	tc.Reset()
+	assert.NoError(t, tc.TryScope(try).Do())
+
+	assert.Equal(t, 2, count, "each cleanup should run exactly once")
+	assert.Nil(t, tc.scope)
+}
+
+func TestScope_ContextOutlivesDo(t *testing.T) {
+	type ctxKey struct{}
+	tc := New()
+
+	parent1, cancel1 := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "first"))
+	var saved context.Context
+	assert.NoError(t, tc.ApplyOptions(WithContext(parent1)).TryCtx(func(ctx context.Context) error {
+		saved = ctx
+		return nil
+	}).Do())
+
+	// 第二次执行不应改变第一次执行交给 try 的 context
+	tc.Reset()
+	parent2 := context.WithValue(context.Background(), ctxKey{}, "second")
+	var second context.Context
+	assert.NoError(t, tc.ApplyOptions(WithContext(parent2)).TryCtx(func(ctx context.Context) error {
+		second = ctx
+		return nil
+	}).Do())
+	tc.Reset()
+
+	assert.Equal(t, "first", saved.Value(ctxKey{}))
+	assert.Equal(t, "second", second.Value(ctxKey{}))
+	assert.NoError(t, saved.Err())
+
+	cancel1()
+	<-saved.Done()
+	assert.ErrorIs(t, saved.Err(), context.Canceled, "the saved context should follow its own parent")
+	assert.NoError(t, second.Err())
+}
+
+func TestScope_ReuseNoAllocs(t *testing.T) {
+	tc := New()
+	try := func(ctx context.Context) error { return nil }
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = tc.TryCtx(try).Do()
+		tc.Reset()
+	})
+	assert.Zero(t, allocs, "a reused block should not allocate a scope it never hands out")
+
+	// 取出过的 Scope 可能被保留，不应在下次执行中复用
+	var first, second *Scope
+	assert.NoError(t, tc.TryScope(func(s *Scope) error { first = s; return nil }).Do())
+	assert.NoError(t, tc.TryScope(func(s *Scope) error { second = s; return nil }).Do())
+	assert.NotSame(t, first, second)
+
+	ran := false
+	first.Defer(func() { ran = true })
+	assert.True(t, ran, "a retained scope should stay closed")
+}
+
+func TestScope_TypedBlock(t *testing.T) {
+	closed := false
+
+	v, err := NewTyped[int]().
+		TryCtx(func(ctx context.Context) (int, error) {
+			ScopeFrom(ctx).Defer(func() { closed = true })
+			return 1, nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.True(t, closed)
+}
+
+func TestScopeFrom_NoScope(t *testing.T) {
+	assert.Nil(t, ScopeFrom(context.Background()))
+}
@@ -0,0 +1,150 @@
+// Package slogtc 通过 log/slog 记录 gotrycatch 块的错误、panic 与取消
+//
+// WithLogger 返回基于 gtc.Observer 的选项，每次 Do 结束时按结果写入一条结构化日志，
+// 记录中包含块名称、耗时、尝试次数与结果；panic 的值与调用栈以 "panic" 分组属性输出
+package slogtc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+)
+
+// 日志记录中使用的属性键
+const (
+	KeyName       = "name"
+	KeyDuration   = "duration"
+	KeyAttempt    = "attempt"
+	KeyOutcome    = "outcome"
+	KeyError      = "error"
+	KeyPanic      = "panic"
+	KeyCatchPanic = "catch_panic"
+)
+
+// config 保存日志的配置
+type config struct {
+	successLevel    *slog.Level // 成功执行的日志级别，nil 表示不记录
+	errorLevel      slog.Level
+	panicLevel      slog.Level
+	catchPanicLevel slog.Level
+	cancelLevel     slog.Level
+}
+
+// Option 定义日志的配置选项
+type Option func(*config)
+
+// WithErrorLevel 指定 try 返回错误时的日志级别，默认为 slog.LevelWarn
+func WithErrorLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.errorLevel = level
+	}
+}
+
+// WithPanicLevel 指定 try 发生 panic 时的日志级别，默认为 slog.LevelError
+func WithPanicLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.panicLevel = level
+	}
+}
+
+// WithCatchPanicLevel 指定 catch 自身发生 panic 时的日志级别，默认为 slog.LevelError
+func WithCatchPanicLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.catchPanicLevel = level
+	}
+}
+
+// WithCancelLevel 指定 context 取消时的日志级别，默认为 slog.LevelInfo
+func WithCancelLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.cancelLevel = level
+	}
+}
+
+// WithSuccessLevel 开启成功执行的日志并指定级别，默认不记录成功的执行
+func WithSuccessLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.successLevel = &level
+	}
+}
+
+// WithLogger 返回将块的执行结果写入 logger 的选项，logger 为 nil 时使用 slog.Default()
+func WithLogger(logger *slog.Logger, opts ...Option) gtc.Option {
+	return gtc.WithObserver(NewObserver(logger, opts...))
+}
+
+// NewObserver 创建将执行结果写入 logger 的 gtc.Observer，便于与其他观察者组合使用
+func NewObserver(logger *slog.Logger, opts ...Option) gtc.Observer {
+	cfg := config{
+		errorLevel:      slog.LevelWarn,
+		panicLevel:      slog.LevelError,
+		catchPanicLevel: slog.LevelError,
+		cancelLevel:     slog.LevelInfo,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &observer{logger: logger, cfg: cfg}
+}
+
+// observer 实现 gtc.Observer，只在 OnDone 中写日志
+type observer struct {
+	logger *slog.Logger
+	cfg    config
+}
+
+func (*observer) OnTryStart(gtc.ExecInfo) {}
+
+func (*observer) OnTryEnd(gtc.ExecInfo) {}
+
+// OnDone 按本次 Do 的结果写入日志；catch 发生 panic 时额外写入一条记录
+func (o *observer) OnDone(info gtc.ExecInfo) {
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ctx := info.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	switch info.Outcome {
+	case gtc.OutcomeSuccess:
+		if o.cfg.successLevel != nil {
+			logger.LogAttrs(ctx, *o.cfg.successLevel, "trycatch: try succeeded", baseAttrs(info)...)
+		}
+	case gtc.OutcomeError:
+		logger.LogAttrs(ctx, o.cfg.errorLevel, "trycatch: try failed", append(baseAttrs(info), slog.Any(KeyError, info.Err))...)
+	case gtc.OutcomeCancelled:
+		logger.LogAttrs(ctx, o.cfg.cancelLevel, "trycatch: try cancelled", append(baseAttrs(info), slog.Any(KeyError, info.Err))...)
+	case gtc.OutcomePanic:
+		logger.LogAttrs(ctx, o.cfg.panicLevel, "trycatch: try panicked", append(baseAttrs(info), panicAttr(info))...)
+	}
+
+	if info.CatchPanic != nil {
+		logger.LogAttrs(ctx, o.cfg.catchPanicLevel, "trycatch: catch panicked",
+			append(baseAttrs(info), slog.Any(KeyCatchPanic, info.CatchPanic))...)
+	}
+}
+
+// baseAttrs 返回每条记录都包含的属性
+func baseAttrs(info gtc.ExecInfo) []slog.Attr {
+	return []slog.Attr{
+		slog.String(KeyName, info.Name),
+		slog.Duration(KeyDuration, info.Duration()),
+		slog.Int(KeyAttempt, info.Attempt),
+		slog.String(KeyOutcome, info.Outcome.String()),
+	}
+}
+
+// panicAttr 返回 panic 分组属性，包含 panic 值与调用栈（panic 被转换为其他错误时没有调用栈）
+func panicAttr(info gtc.ExecInfo) slog.Attr {
+	attrs := []any{slog.Any("value", info.PanicValue)}
+	var pe *gtc.PanicError
+	if errors.As(info.Err, &pe) {
+		attrs = append(attrs, slog.String("stack", pe.StackTrace()))
+	}
+	return slog.Group(KeyPanic, attrs...)
+}
@@ -0,0 +1,142 @@
+package slogtc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	gtc "github.com/shengyanli1982/go-trycatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		out = append(out, record)
+	}
+	return out
+}
+
+func TestWithLogger_Error(t *testing.T) {
+	var buf bytes.Buffer
+
+	_ = gtc.NewWithOptions(gtc.WithName("op"), WithLogger(newLogger(&buf))).
+		Try(func() error { return errors.New("boom") }).
+		Do()
+
+	recs := records(t, &buf)
+	assert.Len(t, recs, 1)
+	assert.Equal(t, "WARN", recs[0]["level"])
+	assert.Equal(t, "trycatch: try failed", recs[0]["msg"])
+	assert.Equal(t, "op", recs[0][KeyName])
+	assert.Equal(t, "boom", recs[0][KeyError])
+	assert.Equal(t, "error", recs[0][KeyOutcome])
+	assert.Equal(t, float64(1), recs[0][KeyAttempt])
+	assert.Contains(t, recs[0], KeyDuration)
+}
+
+func TestWithLogger_Panic(t *testing.T) {
+	var buf bytes.Buffer
+
+	_ = gtc.NewWithOptions(gtc.WithName("op"), WithLogger(newLogger(&buf))).
+		Try(func() error { panic("kaboom") }).
+		Do()
+
+	recs := records(t, &buf)
+	assert.Len(t, recs, 1)
+	assert.Equal(t, "ERROR", recs[0]["level"])
+	assert.Equal(t, "trycatch: try panicked", recs[0]["msg"])
+	group, ok := recs[0][KeyPanic].(map[string]any)
+	assert.True(t, ok, "panic should be a group attribute")
+	assert.Equal(t, "kaboom", group["value"])
+	assert.Contains(t, group["stack"], "TestWithLogger_Panic")
+}
+
+func TestWithLogger_CatchPanic(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.Panics(t, func() {
+		_ = gtc.NewWithOptions(WithLogger(newLogger(&buf))).
+			Try(func() error { return errors.New("boom") }).
+			Catch(func(error) { panic("catch boom") }).
+			Do()
+	})
+
+	recs := records(t, &buf)
+	assert.Len(t, recs, 2)
+	assert.Equal(t, "trycatch: catch panicked", recs[1]["msg"])
+	assert.Equal(t, "catch boom", recs[1][KeyCatchPanic])
+}
+
+func TestWithLogger_Cancelled(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = gtc.NewWithOptions(gtc.WithContext(ctx), WithLogger(newLogger(&buf))).
+		Try(func() error { return nil }).
+		Do()
+
+	recs := records(t, &buf)
+	assert.Len(t, recs, 1)
+	assert.Equal(t, "INFO", recs[0]["level"])
+	assert.Equal(t, "trycatch: try cancelled", recs[0]["msg"])
+	assert.Equal(t, "cancelled", recs[0][KeyOutcome])
+}
+
+func TestWithLogger_Success(t *testing.T) {
+	var buf bytes.Buffer
+
+	_ = gtc.NewWithOptions(WithLogger(newLogger(&buf))).Try(func() error { return nil }).Do()
+	assert.Empty(t, buf.String(), "success is not logged by default")
+
+	_ = gtc.NewWithOptions(WithLogger(newLogger(&buf), WithSuccessLevel(slog.LevelDebug))).
+		Try(func() error { return nil }).
+		Do()
+	recs := records(t, &buf)
+	assert.Len(t, recs, 1)
+	assert.Equal(t, "DEBUG", recs[0]["level"])
+}
+
+func TestWithLogger_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+	opts := []Option{
+		WithErrorLevel(slog.LevelError),
+		WithPanicLevel(slog.LevelWarn),
+		WithCancelLevel(slog.LevelDebug),
+	}
+
+	_ = gtc.NewWithOptions(WithLogger(logger, opts...)).Try(func() error { return errors.New("boom") }).Do()
+	_ = gtc.NewWithOptions(WithLogger(logger, opts...)).Try(func() error { panic("boom") }).Do()
+
+	recs := records(t, &buf)
+	assert.Len(t, recs, 2)
+	assert.Equal(t, "ERROR", recs[0]["level"])
+	assert.Equal(t, "WARN", recs[1]["level"])
+}
+
+func TestWithLogger_NilUsesDefault(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(newLogger(&buf))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	_ = gtc.NewWithOptions(WithLogger(nil)).Try(func() error { return errors.New("boom") }).Do()
+
+	assert.Len(t, records(t, &buf), 1)
+}
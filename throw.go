@@ -0,0 +1,35 @@
+package gotrycatch
+
+// thrownError 是 Throw 抛出的 panic 值，恢复时还原为 err 本身
+type thrownError struct {
+	err error
+}
+
+// Error 返回被抛出的错误的描述，未被任何块恢复而导致崩溃时也能给出可读的信息
+func (e *thrownError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap 返回被抛出的错误
+func (e *thrownError) Unwrap() error {
+	return e.err
+}
+
+// Throw 以 panic 的方式中止当前的 try（或 catch、finally），由外层的块恢复后将 err 原样作为错误处理
+// 与普通的 panic 不同，被抛出的错误不会包装为 *PanicError，不经过 PanicConverter，
+// 不通知 panic 处理函数，也不受 WithRepanicPolicy 影响；观察者看到的结果为 OutcomeError，
+// 配置重试时按普通错误参与重试。适用于在深层调用中直接结束 try，例如 HTTP 处理函数中返回 404。
+// err 为 nil 时不做任何事
+func Throw(err error) {
+	if err != nil {
+		panic(&thrownError{err: err})
+	}
+}
+
+// thrown 判断恢复值 r 是否由 Throw 抛出，是则返回被抛出的错误
+func thrown(r any) (error, bool) {
+	if t, ok := r.(*thrownError); ok {
+		return t.err, true
+	}
+	return nil, false
+}
@@ -0,0 +1,155 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errThrown = errors.New("thrown error")
+
+func TestThrow_Do(t *testing.T) {
+	resetDefaults(t)
+	reported := false
+	RegisterPanicHandler(func(*PanicError) { reported = true })
+	var events []string
+	var caught error
+
+	err := NewWithOptions(WithHooks(Hooks{
+		OnTryEnd: func(err error) { events = append(events, "end") },
+		OnCatch:  func(err error) { events = append(events, "catch") },
+	})).
+		Try(func() error {
+			Throw(errThrown)
+			return nil
+		}).
+		Catch(func(err error) { caught = err }).
+		Do()
+
+	assert.Same(t, errThrown, err, "a thrown error should be returned as is")
+	assert.Same(t, errThrown, caught)
+	assert.Equal(t, []string{"end", "catch"}, events, "a thrown error should be handled like a returned error")
+	assert.False(t, reported, "a thrown error is not a panic")
+}
+
+func TestThrow_Nil(t *testing.T) {
+	assert.NotPanics(t, func() { Throw(nil) })
+}
+
+func TestThrow_Uncaught(t *testing.T) {
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if assert.True(t, ok) {
+			assert.Equal(t, "thrown error", err.Error())
+			assert.ErrorIs(t, err, errThrown)
+		}
+	}()
+	Throw(errThrown)
+}
+
+func TestThrow_Observer(t *testing.T) {
+	obs := &recordingObserver{}
+
+	_ = NewWithOptions(WithObserver(obs)).
+		Try(func() error {
+			Throw(errThrown)
+			return nil
+		}).
+		Do()
+
+	assert.Equal(t, []string{"start", "end", "done"}, obs.events)
+	done := obs.infos[len(obs.infos)-1]
+	assert.Equal(t, OutcomeError, done.Outcome)
+	assert.Nil(t, done.PanicValue)
+}
+
+func TestThrow_IgnoresRepanicPolicyAndConverter(t *testing.T) {
+	converted := false
+	var err error
+
+	assert.NotPanics(t, func() {
+		err = NewWithOptions(
+			WithRepanicPolicy(func(any) bool { return true }),
+			WithPanicConverter(PanicConverterFunc(func(any, []byte) error {
+				converted = true
+				return nil
+			})),
+		).
+			Try(func() error {
+				Throw(errThrown)
+				return nil
+			}).
+			Do()
+	})
+
+	assert.Same(t, errThrown, err)
+	assert.False(t, converted)
+}
+
+func TestThrow_Retry(t *testing.T) {
+	attempts := 0
+
+	err := NewWithOptions(WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() error {
+			attempts++
+			if attempts < 3 {
+				Throw(errThrown)
+			}
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts, "a thrown error should be retried like a returned error")
+}
+
+func TestThrow_EnforceDeadline(t *testing.T) {
+	err := NewWithOptions(WithTimeout(time.Second), WithEnforceDeadline()).
+		TryCtx(func(context.Context) error {
+			Throw(errThrown)
+			return nil
+		}).
+		Do()
+
+	assert.Same(t, errThrown, err)
+}
+
+func TestThrow_FinallyAndCatch(t *testing.T) {
+	finallyErr := errors.New("finally error")
+
+	err := New().
+		Try(func() error { return nil }).
+		Finally(func() { Throw(finallyErr) }).
+		Do()
+	assert.Same(t, finallyErr, err)
+
+	err = New().
+		Try(func() error { return errors.New("original") }).
+		Catch(func(error) { Throw(errThrown) }).
+		Go().
+		Wait()
+	assert.Same(t, errThrown, err, "an error thrown by catch should reach the outer recovery as is")
+}
+
+func TestThrow_Generics(t *testing.T) {
+	v, err := TryWithResult(func() (int, error) {
+		Throw(errThrown)
+		return 1, nil
+	})
+
+	assert.Equal(t, 0, v)
+	assert.Same(t, errThrown, err)
+
+	v, err = NewTyped[int]().
+		Try(func() (int, error) {
+			Throw(errThrown)
+			return 1, nil
+		}).
+		Do()
+	assert.Equal(t, 0, v)
+	assert.Same(t, errThrown, err)
+}
@@ -0,0 +1,77 @@
+package gotrycatch
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+)
+
+// tryResult 保存在独立 goroutine 中执行的 try 的结果
+type tryResult struct {
+	err      error // try 返回的错误
+	panicErr error // try 发生 panic 时转换得到的 *PanicError
+}
+
+// 独立 goroutine 中 try 的状态
+const (
+	detachedRunning   int32 = iota // 正在运行
+	detachedFinished               // 在被放弃之前结束
+	detachedAbandoned              // 已被放弃，由后台 goroutine 归还隔板名额
+)
+
+// tryDetached 在独立的 goroutine 中执行 try，并在 ctx 结束时立即返回 ctx.Err()
+// 被放弃的 try 会在后台继续运行直至结束，其结果将被丢弃，并通过 OnAbandon 钩子通知调用方。
+// try 中的 panic 会在当前 goroutine 中重新抛出，由 Do 统一处理；try 调用 runtime.Goexit 时返回 ErrGoexit。
+// 持有隔板名额时，被放弃的 try 由后台 goroutine 在真正结束后归还名额，保证同时运行的 try 不超过隔板的限制
+func (tc *TryCatchBlock) tryDetached(ctx context.Context, d *defaults, adm *admission) error {
+	// 复制所需字段，避免 Do 返回、块被 Reset 复用后后台 goroutine 读取到新的状态
+	try, tryCtx, name := tc.try, tc.tryCtx, tc.name
+	middlewares := slices.Clone(tc.opts.middlewares)
+	done := make(chan tryResult, 1)
+
+	// state 决定由谁归还隔板名额：try 先结束时仍由 Do 归还，先被放弃时转交给后台 goroutine
+	var bulkhead *Bulkhead
+	if adm.bulkhead {
+		bulkhead = tc.opts.bulkhead
+	}
+	var state atomic.Int32
+
+	go func() {
+		var result tryResult
+		returned := false
+		defer func() {
+			r := recover()
+			if thrownErr, ok := thrown(r); ok {
+				result.err = thrownErr
+			} else if r != nil {
+				result.panicErr = newPanicError(r, name, 1, d)
+			} else if !returned {
+				result.err = ErrGoexit
+			}
+			if bulkhead != nil && !state.CompareAndSwap(detachedRunning, detachedFinished) {
+				bulkhead.Release()
+			}
+			done <- result
+		}()
+		result.err = callChain(ctx, try, tryCtx, middlewares)
+		returned = true
+	}()
+
+	select {
+	case result := <-done:
+		if result.panicErr != nil {
+			panic(result.panicErr)
+		}
+		return result.err
+	case <-ctx.Done():
+		err := ctx.Err()
+		if bulkhead != nil && state.CompareAndSwap(detachedRunning, detachedAbandoned) {
+			adm.bulkhead = false
+		}
+		if tc.observed() {
+			tc.opts.info.Abandoned = true
+		}
+		tc.hookAbandon(d, err)
+		return err
+	}
+}
@@ -0,0 +1,159 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout_Cooperative(t *testing.T) {
+	var hasDeadline bool
+
+	err := New().
+		ApplyOptions(WithTimeout(20 * time.Millisecond)).
+		TryCtx(func(ctx context.Context) error {
+			_, hasDeadline = ctx.Deadline()
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Do()
+
+	assert.True(t, hasDeadline, "context passed to TryCtx should carry the timeout")
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestWithTimeout_WaitsWithoutEnforcement(t *testing.T) {
+	err := New().
+		ApplyOptions(WithTimeout(10 * time.Millisecond)).
+		Try(func() error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err, "without enforcement Do waits for try to return")
+}
+
+func TestWithEnforceDeadline_ReturnsOnTimeout(t *testing.T) {
+	var (
+		abandonedErr error
+		caughtErr    error
+		finallyCount int32
+		release      = make(chan struct{})
+	)
+	defer close(release)
+
+	start := time.Now()
+	err := New().
+		ApplyOptions(
+			WithTimeout(20*time.Millisecond),
+			WithEnforceDeadline(),
+			WithHooks(Hooks{
+				OnAbandon: func(err error) { abandonedErr = err },
+			}),
+		).
+		Try(func() error {
+			<-release
+			return nil
+		}).
+		Catch(func(err error) {
+			caughtErr = err
+		}).
+		Finally(func() {
+			atomic.AddInt32(&finallyCount, 1)
+		}).
+		Do()
+	elapsed := time.Since(start)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, elapsed, time.Second, "Do should return as soon as the deadline passes")
+	assert.Equal(t, context.DeadlineExceeded, abandonedErr, "OnAbandon should report the abandoned try")
+	assert.Equal(t, context.DeadlineExceeded, caughtErr, "catch should receive the deadline error")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finallyCount), "finally should run exactly once")
+}
+
+func TestWithEnforceDeadline_ParentContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	err := New().
+		ApplyOptions(WithContext(ctx), WithEnforceDeadline()).
+		Try(func() error {
+			<-release
+			return nil
+		}).
+		Do()
+
+	assert.Equal(t, context.DeadlineExceeded, err, "enforcement should also apply to WithContext deadlines")
+}
+
+func TestWithEnforceDeadline_FinishesInTime(t *testing.T) {
+	tryErr := errors.New("try error")
+	abandoned := false
+
+	err := New().
+		ApplyOptions(
+			WithTimeout(time.Second),
+			WithEnforceDeadline(),
+			WithHooks(Hooks{
+				OnAbandon: func(error) { abandoned = true },
+			}),
+		).
+		TryCtx(func(ctx context.Context) error {
+			return tryErr
+		}).
+		Do()
+
+	assert.Equal(t, tryErr, err)
+	assert.False(t, abandoned)
+}
+
+func TestWithEnforceDeadline_PanicInTry(t *testing.T) {
+	var caughtErr error
+
+	err := New().
+		ApplyOptions(WithTimeout(time.Second), WithEnforceDeadline(), WithName("detached")).
+		Try(func() error {
+			panic("detached panic")
+		}).
+		Catch(func(err error) {
+			caughtErr = err
+		}).
+		Do()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "panic on the detached goroutine should be recovered")
+	assert.Equal(t, "detached panic", panicErr.Value)
+	assert.Equal(t, "detached", panicErr.Name)
+	assert.Equal(t, err, caughtErr)
+}
+
+func TestWithEnforceDeadline_NoDeadlineRunsInline(t *testing.T) {
+	called := false
+
+	err := New().
+		ApplyOptions(WithEnforceDeadline()).
+		Try(func() error {
+			called = true
+			return nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestWithTimeout_Reset(t *testing.T) {
+	tc := NewWithOptions(WithTimeout(time.Second), WithEnforceDeadline())
+
+	tc.Reset()
+
+	assert.Zero(t, tc.options().timeout, "timeout should be cleared after Reset")
+	assert.False(t, tc.options().enforceDeadline, "enforceDeadline should be cleared after Reset")
+}
@@ -2,34 +2,108 @@ package gotrycatch
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"time"
 )
 
-// stringError 是一个轻量级的 error 实现，用于将非 error 类型的 panic 值转换为 error。
-// 相比 fmt.Errorf，它避免了额外的分配和 fmt 包的开销。
-type stringError struct {
-	msg string
+// ErrGoexit 表示 try 调用了 runtime.Goexit（例如测试中的 t.FailNow）而没有正常返回
+var ErrGoexit = errors.New("gotrycatch: try called runtime.Goexit")
+
+// finallyGuard 在隔离环境中执行 finally 函数，将 finally 内部的 panic 转换为错误返回
+// fn 与 fnErr 最多设置其一；返回 nil 表示 finally 正常执行完毕
+func finallyGuard(fn func(), fnErr func() error, name string, d *defaults, converter PanicConverter) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = convertPanic(r, name, 1, d, converter)
+		}
+	}()
+	if fnErr != nil {
+		return fnErr()
+	}
+	if fn != nil {
+		fn()
+	}
+	return nil
 }
 
-func (e stringError) Error() string { return e.msg }
+// joinErrors 合并主错误与附加错误，任一方为 nil 时直接返回另一方
+func joinErrors(primary, extra error) error {
+	switch {
+	case extra == nil:
+		return primary
+	case primary == nil:
+		return extra
+	default:
+		return errors.Join(primary, extra)
+	}
+}
 
-// catchGuard 在隔离环境中执行 catch 函数，捕获 catch 内部的 panic 并返回。
-// 返回 nil 表示 catch 正常执行完毕；返回非 nil 表示 catch 发生了 panic。
-func catchGuard(fn func(error), err error) (panicVal any) {
-	defer func() { panicVal = recover() }()
-	fn(err)
-	return
+// callTry 执行 try 或 tryCtx，二者同时设置时 try 优先
+func callTry(ctx context.Context, try func() error, tryCtx func(context.Context) error) error {
+	if try != nil {
+		return try()
+	}
+	return tryCtx(ctx)
 }
 
 // TryCatchBlock 实现 try-catch-finally 错误处理模式
 type TryCatchBlock struct {
-	try     func() error              // 待执行的函数，可能返回错误
-	tryCtx  func(context.Context) error // 上下文感知的 try 函数，与 try 互斥
-	catch   func(error)               // 错误处理函数
-	finally func()                    // 清理函数，在所有情况下都会执行
-	ctx     context.Context           // 用于取消和超时的上下文
-	hooks   Hooks                     // 监控执行的钩子
-	name    string                    // 块的名称标识符
+	try        func() error                // 待执行的函数，可能返回错误
+	tryCtx     func(context.Context) error // 上下文感知的 try 函数，与 try 互斥
+	catch      func(error)                 // 兜底的错误处理函数，在没有 catch 子句匹配时执行
+	catchErr   func(error) error           // 可替换错误的兜底处理函数，与 catch 互斥
+	clauses    []catchClause               // 按注册顺序匹配的 catch 子句
+	finally    func()                      // 清理函数，在所有情况下都会执行
+	finallyErr func() error                // 可返回错误的清理函数，与 finally 互斥
+	ctx        context.Context             // 用于取消和超时的上下文
+	hooks      Hooks                       // 监控执行的钩子
+	opts       *blockOptions               // 不常用的块级配置，nil 表示均未设置
+	scope      *scopeFrame                 // 本次执行的 Scope，nil 表示未准备
+	spare      *scopeFrame                 // 缓存的 scopeFrame，父 context 为 context.Background() 时复用
+	name       string                      // 块的名称标识符
+}
+
+// blockOptions 保存超时、重试、观察者等不常用的块级配置
+// 只在应用了对应选项时分配，使只使用 try / catch / finally 的块保持精简；Reset 保留已分配的实例供对象池复用
+type blockOptions struct {
+	timeout         time.Duration  // try 的超时时间，0 表示不设超时
+	enforceDeadline bool           // 是否在 context 结束时立即返回，不再等待 try 完成
+	noDefaults      bool           // 是否不使用进程级的默认钩子与 panic 处理函数
+	retry           RetryPolicy    // try 失败后的重试策略
+	converter       PanicConverter // 块级的 panic 转换器，优先于进程级配置
+	repanic         func(any) bool // 判断 panic 是否需要重新抛出的策略
+	observers       []Observer     // 按添加顺序通知的观察者
+	middlewares     []Middleware   // 由外向内包裹 try 的中间件
+	breaker         CircuitBreaker // 熔断器，nil 表示不使用
+	bulkhead        *Bulkhead      // 并发隔板，nil 表示不使用
+	info            ExecInfo       // 当前执行的信息，仅在有观察者时维护
+}
+
+// noOptions 是未设置任何配置的块读取到的零值，只读
+// info 只在有观察者时写入，而添加观察者会分配块自己的 blockOptions，因此 noOptions 不会被修改
+var noOptions blockOptions
+
+// options 返回块的配置，用于读取；未设置任何配置时返回只读的零值
+func (tc *TryCatchBlock) options() *blockOptions {
+	if tc.opts == nil {
+		return &noOptions
+	}
+	return tc.opts
+}
+
+// mutableOptions 返回块的配置，用于写入；首次调用时分配
+func (tc *TryCatchBlock) mutableOptions() *blockOptions {
+	if tc.opts == nil {
+		tc.opts = &blockOptions{}
+	}
+	return tc.opts
+}
+
+// reset 清理配置，保留切片的底层数组供复用，同时释放对元素的引用
+func (o *blockOptions) reset() {
+	clear(o.observers)
+	clear(o.middlewares)
+	*o = blockOptions{observers: o.observers[:0], middlewares: o.middlewares[:0]}
 }
 
 // New 返回一个 TryCatchBlock 实例
@@ -43,10 +117,18 @@ func (tc *TryCatchBlock) Reset() {
 	tc.try = nil
 	tc.tryCtx = nil
 	tc.catch = nil
+	tc.catchErr = nil
+	clear(tc.clauses)
+	tc.clauses = tc.clauses[:0]
 	tc.finally = nil
+	tc.finallyErr = nil
 	tc.ctx = nil
 	tc.hooks = Hooks{}
 	tc.name = ""
+	if tc.opts != nil {
+		tc.opts.reset()
+	}
+	tc.scope = nil
 }
 
 // Try 设置待执行的函数
@@ -61,112 +143,244 @@ func (tc *TryCatchBlock) TryCtx(try func(context.Context) error) *TryCatchBlock
 	return tc
 }
 
-// Catch 设置错误处理函数
+// Catch 设置兜底的错误处理函数，在 CatchIs / CatchAs 子句都不匹配时执行
+// Do 仍返回原错误。与 CatchErr 互斥，后设置的生效
 func (tc *TryCatchBlock) Catch(catch func(error)) *TryCatchBlock {
 	tc.catch = catch
+	tc.catchErr = nil
+	return tc
+}
+
+// CatchErr 设置可以恢复、替换或重新抛出错误的兜底处理函数
+// 返回 nil 表示错误已处理，Do 返回 nil；返回新的错误会替换 Do 的返回值；返回原错误即重新抛出。
+// 与 Catch 互斥，后设置的生效
+func (tc *TryCatchBlock) CatchErr(catch func(error) error) *TryCatchBlock {
+	tc.catchErr = catch
+	tc.catch = nil
 	return tc
 }
 
 // Finally 设置清理函数
+// finally 中的 panic 会被转换为 *PanicError，并与主错误合并后由 Do 返回。与 FinallyErr 互斥，后设置的生效
 func (tc *TryCatchBlock) Finally(finally func()) *TryCatchBlock {
 	tc.finally = finally
+	tc.finallyErr = nil
+	return tc
+}
+
+// FinallyErr 设置可返回错误的清理函数，适用于 Close() 等清理操作
+// 返回的错误会通过 errors.Join 与主错误合并后由 Do 返回。与 Finally 互斥，后设置的生效
+func (tc *TryCatchBlock) FinallyErr(finally func() error) *TryCatchBlock {
+	tc.finallyErr = finally
+	tc.finally = nil
 	return tc
 }
 
 // Do 执行 try-catch-finally 流程，返回错误
-// 返回 try 返回的错误或 panic 转换的错误
+// 返回 try 返回的错误，或 panic 转换得到的错误（默认为 *PanicError，可通过 PanicConverter 定制）；使用 CatchErr 等形式时返回处理函数给出的错误
+//
+// 如果 try 调用了 runtime.Goexit（例如测试中的 t.FailNow），当前 goroutine 无法被阻止退出，
+// Do 不会返回；但 OnTryEnd、catch 与 finally 仍会以 ErrGoexit 作为错误执行，以便记录这次异常退出
 func (tc *TryCatchBlock) Do() (err error) {
+	st := execState{goexit: true, d: tc.defaults()}
+	st.converter = tc.panicConverter(st.d)
+
+	// recover() 必须由 defer 函数直接调用，恢复值再交给 complete 处理
+	defer func() {
+		err = tc.complete(&st, recover())
+	}()
+
+	if tc.observed() {
+		tc.observeBegin()
+	}
+	st.tryErr, st.ctxErr = tc.execute(st.d, &st.adm)
+	st.goexit = false
+	return
+}
+
+// execState 保存一次 Do 在 try 阶段得到的状态，由 complete 在 defer 中处理
+type execState struct {
+	tryErr    error          // try 返回的错误
+	ctxErr    error          // context 在执行前已结束时的 ctx.Err()
+	adm       admission      // 获取的准入资源
+	goexit    bool           // 主体正常返回前保持为 true，recover() 为 nil 时据此识别 runtime.Goexit
+	d         *defaults      // 本次执行使用的进程级配置
+	converter PanicConverter // 本次执行使用的 panic 转换器
+}
+
+// complete 处理 try 阶段的结果：执行 catch、Scope 清理与 finally，返回 Do 的错误
+// r 为 Do 的 defer 中 recover() 的返回值；catch 的 panic 与命中重新抛出策略的 panic 在 finally 之后重新抛出
+func (tc *TryCatchBlock) complete(st *execState, r any) (err error) {
 	var (
-		ctxCancelled  bool
-		catchCalled   bool
 		catchPanicErr any
-		returnedErr   error
+		repanicVal    any
+		returnedErr   = st.tryErr
+		tryErr        error // catch 处理之前的错误，用于通知观察者
+		tryPanic      any   // try 的原始 panic 值，用于通知观察者
+		d, converter  = st.d, st.converter
+		goexit        = st.goexit
 	)
 
-	defer func() {
-		// recover() 必须在 defer 函数的顶层调用（不能在内层闭包中调用）
-		r := recover()
-
-		// 1. 处理 panic
-		if r != nil {
-			var panicErr error
-			switch v := r.(type) {
-			case error:
-				panicErr = v
-			case string:
-				panicErr = stringError{v}
-			default:
-				panicErr = stringError{fmt.Sprintf("%v", v)}
-			}
-			if tc.hooks.OnCatch != nil {
-				tc.hooks.OnCatch(panicErr)
-			}
-			if tc.catch != nil && !catchCalled {
-				catchPanicErr = catchGuard(tc.catch, panicErr)
-			}
-			returnedErr = panicErr
-			err = panicErr
-		} else if !ctxCancelled {
-			// 2. 正常路径：处理 try() 返回的错误，调用 catch
-			if returnedErr != nil && tc.catch != nil {
-				catchCalled = true
-				if tc.hooks.OnCatch != nil {
-					tc.hooks.OnCatch(returnedErr)
-				}
-				catchPanicErr = catchGuard(tc.catch, returnedErr)
-			}
-			err = returnedErr
-		}
+	// try 阶段已结束，先归还隔板名额，避免 catch / finally 占用并发名额
+	if st.adm.bulkhead {
+		tc.opts.bulkhead.Release()
+	}
+
+	// 由 Throw 抛出的错误等同于 try 返回了该错误
+	if thrownErr, ok := thrown(r); ok {
+		r, goexit = nil, false
+		returnedErr = thrownErr
+		tc.hookTryEndOnly(d, returnedErr)
+	}
+
+	// 0. 主体既没有返回也没有 panic，说明 try 调用了 runtime.Goexit，按 ErrGoexit 错误处理
+	if r == nil && goexit {
+		returnedErr = ErrGoexit
+		tc.hookTryEndOnly(d, returnedErr)
+	}
 
-		// finally 始终执行（catch panic 已被 catchGuard 隔离）
-		if tc.hooks.OnFinally != nil {
-			tc.hooks.OnFinally()
+	// 1. 处理 panic：命中重新抛出策略的 panic 不转换为错误，在 finally 之后以原始值重新抛出
+	if r != nil && tc.shouldRepanic(r) {
+		repanicVal = panicValue(r)
+		tryPanic = repanicVal
+	} else if r != nil {
+		// 跳过 complete 与 Do 的 defer 函数，调用栈从 panic 处开始
+		panicErr := convertPanic(r, tc.name, 2, d, converter)
+		returnedErr = panicErr
+		tryErr, tryPanic = panicErr, panicValue(r)
+		tc.hookCatch(d, panicErr)
+		if catch := tc.handler(panicErr); catch.valid() {
+			returnedErr, catchPanicErr = catch.call(panicErr)
 		}
-		if tc.finally != nil {
-			tc.finally()
+		err = returnedErr
+	} else if st.ctxErr == nil {
+		// 2. 正常路径：处理 try() 返回的错误，调用 catch
+		tryErr = returnedErr
+		if returnedErr != nil {
+			if catch := tc.handler(returnedErr); catch.valid() {
+				tc.hookCatch(d, returnedErr)
+				returnedErr, catchPanicErr = catch.call(returnedErr)
+			}
 		}
+		err = returnedErr
+	} else {
+		// 3. context 在执行前已结束：不执行 try 与 catch，返回 ctx.Err()
+		tc.hookCancel(d, st.ctxErr)
+		tryErr = st.ctxErr
+		err = st.ctxErr
+	}
+	if catchPanicErr != nil {
+		tc.hookCatchPanic(d, catchPanicErr)
+	}
+	if st.adm.breaker {
+		tc.recordBreaker(st.adm.generation, tryErr, tryPanic)
+	}
+	// 观察者在隔离环境中执行，其 panic 被转换为错误并合并到返回值中
+	if tc.observed() {
+		err = joinErrors(err, tc.observeDone(tryErr, tryPanic, catchPanicErr, d, converter))
+	}
 
-		// 如果 catch 产生了 panic，向上传播
-		if catchPanicErr != nil {
-			panic(catchPanicErr)
-		}
-	}()
+	// Scope 中注册的清理函数先于 finally 按后进先出的顺序执行，其错误被合并到返回值中
+	cleanupErr := tc.closeScope(converter)
+	err = joinErrors(err, cleanupErr)
+
+	// finally 始终执行（catch panic 已被隔离），finally 自身的 panic 与错误被合并到返回值中
+	finallyErr := tc.hookFinally(d, converter)
+	if tc.finally != nil || tc.finallyErr != nil {
+		finallyErr = joinErrors(finallyErr, finallyGuard(tc.finally, tc.finallyErr, tc.name, d, converter))
+	}
+	err = joinErrors(err, finallyErr)
+	if tc.observed() {
+		err = joinErrors(err, tc.observeFinally(joinErrors(cleanupErr, finallyErr), d, converter))
+	}
 
+	// 如果 catch 产生了 panic 或 try 的 panic 命中重新抛出策略，向上传播（此时 finally 的错误无法通过返回值传递）
+	if catchPanicErr != nil {
+		panic(catchPanicErr)
+	}
+	if repanicVal != nil {
+		panic(repanicVal)
+	}
+	return err
+}
+
+// execute 执行 try 阶段，返回 try 的错误；context 在执行前已结束时通过 ctxErr 返回 ctx.Err()
+// 获取的隔板名额与熔断器放行记录在 adm 中，由 Do 在结束时归还与报告（包括 try 发生 panic 的情况）
+func (tc *TryCatchBlock) execute(d *defaults, adm *admission) (tryErr, ctxErr error) {
 	if tc.try == nil && tc.tryCtx == nil {
-		return nil
+		return nil, nil
 	}
 
-	// 检查 context 是否已取消（不再 early return，让 defer 统一处理 finally）
-	if tc.ctx != nil {
+	// 根据 WithTimeout 派生带超时的 context
+	o := tc.options()
+	ctx := tc.ctx
+	if o.timeout > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	// 检查 context 是否已取消（不再 early return，让 Do 的 defer 统一处理 finally）
+	if ctx != nil {
 		select {
-		case <-tc.ctx.Done():
-			ctxCancelled = true
-			err = tc.ctx.Err()
-			return
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		default:
 		}
+	} else {
+		ctx = context.Background()
 	}
 
-	// 执行 OnTryStart 钩子
-	if tc.hooks.OnTryStart != nil {
-		tc.hooks.OnTryStart()
+	// 隔板或熔断器拒绝时跳过 try，错误按普通错误经过 catch 与 finally
+	if o.bulkhead != nil {
+		if err := o.bulkhead.Acquire(ctx); err != nil {
+			return err, nil
+		}
+		adm.bulkhead = true
 	}
-
-	// 执行 try 函数
-	if tc.try != nil {
-		returnedErr = tc.try()
-	} else if tc.tryCtx != nil {
-		ctx := tc.ctx
-		if ctx == nil {
-			ctx = context.Background()
+	if o.breaker != nil {
+		generation, err := o.breaker.Allow()
+		if err != nil {
+			return err, nil
 		}
-		returnedErr = tc.tryCtx(ctx)
+		adm.breaker, adm.generation = true, generation
 	}
 
-	// 执行 OnTryEnd 钩子
-	if tc.hooks.OnTryEnd != nil {
-		tc.hooks.OnTryEnd(returnedErr)
+	// 准备供 try 注册清理函数的 Scope，由 Do 在 finally 之前执行清理
+	tc.openScope(ctx, d)
+
+	// 配置了重试策略时按策略执行，catch 与 finally 只在最后一次尝试后执行
+	if o.retry.MaxAttempts > 1 {
+		return tc.tryWithRetry(ctx, d, adm), nil
 	}
 
-	return
+	// 执行 OnTryStart 钩子
+	tc.hookTryStart(d, ctx, 1)
+
+	// 执行 try 函数
+	tryErr = tc.runTry(ctx, d, adm)
+
+	// 执行 OnTryEnd 钩子
+	tc.hookTryEnd(d, tryErr)
+
+	return tryErr, nil
+}
+
+// admission 记录本次执行获取的准入资源
+type admission struct {
+	bulkhead   bool   // 是否获取了隔板名额
+	breaker    bool   // 熔断器是否放行
+	generation uint64 // 熔断器放行时返回的代
+}
+
+// runTry 执行一次 try；开启截止时间强制模式且 ctx 可被取消时，在独立 goroutine 中执行
+func (tc *TryCatchBlock) runTry(ctx context.Context, d *defaults, adm *admission) error {
+	o := tc.options()
+	if o.enforceDeadline && ctx.Done() != nil {
+		return tc.tryDetached(tc.tryContext(ctx), d, adm)
+	}
+	return callChain(tc.tryContext(ctx), tc.try, tc.tryCtx, o.middlewares)
 }
@@ -1,73 +1,143 @@
 package gotrycatch
 
-import (
-	"fmt"
-)
-
-// TryWithResult 执行带返回值的函数，捕获 panic 并转换为错误
+// TryWithResult 执行带返回值的函数，捕获 panic 并转换为错误（默认为 *PanicError）
 func TryWithResult[T any](fn func() (T, error)) (result T, err error) {
+	d := loadDefaults()
+
 	defer func() {
 		if r := recover(); r != nil {
-			switch v := r.(type) {
-			case error:
-				err = v
-			case string:
-				err = stringError{v}
-			default:
-				err = stringError{fmt.Sprintf("%v", v)}
-			}
+			err = convertPanic(r, "", 1, d, d.panicConverter())
+			d.onCatch(err)
 		}
 	}()
 
-	return fn()
+	d.onTryStart()
+	result, err = fn()
+	d.onTryEnd(err)
+	return
 }
 
 // TryWithResultAndFinally 类似 TryWithResult，但额外接受 finally 处理器
+// 与 Do 一样，finally 中的 panic 被转换为错误并与主错误合并（TryCatchR 与 TryCatchRErr 同样如此）
 func TryWithResultAndFinally[T any](fn func() (T, error), finally func()) (result T, err error) {
+	d := loadDefaults()
+
 	defer func() {
 		if r := recover(); r != nil {
-			switch v := r.(type) {
-			case error:
-				err = v
-			case string:
-				err = stringError{v}
-			default:
-				err = stringError{fmt.Sprintf("%v", v)}
-			}
+			err = convertPanic(r, "", 1, d, d.panicConverter())
+			d.onCatch(err)
 		}
+		err = joinErrors(err, d.onFinally("", d.panicConverter()))
 		if finally != nil {
-			finally()
+			err = joinErrors(err, finallyGuard(finally, nil, "", d, d.panicConverter()))
 		}
 	}()
 
-	return fn()
+	d.onTryStart()
+	result, err = fn()
+	d.onTryEnd(err)
+	return
 }
 
-// TryCatchR 执行带泛型返回值的 try-catch-finally 流程，捕获 panic 并转换为错误
+// TryCatchR 执行带泛型返回值的 try-catch-finally 流程，捕获 panic 并转换为错误（默认为 *PanicError）
 func TryCatchR[T any](fn func() (T, error), catch func(error), finally func()) (result T, err error) {
 	var catchPanicErr any
+	d := loadDefaults()
 
 	defer func() {
 		if r := recover(); r != nil {
-			switch v := r.(type) {
-			case error:
-				err = v
-			case string:
-				err = stringError{v}
-			default:
-				err = stringError{fmt.Sprintf("%v", v)}
+			err = convertPanic(r, "", 1, d, d.panicConverter())
+			d.onCatch(err)
+			if catch != nil {
+				err, catchPanicErr = catchHandler{fn: catch}.call(err)
 			}
+		} else if err != nil && catch != nil {
+			d.onCatch(err)
+			err, catchPanicErr = catchHandler{fn: catch}.call(err)
 		}
-		if err != nil && catch != nil {
-			catchPanicErr = catchGuard(catch, err)
+		if catchPanicErr != nil {
+			d.onCatchPanic(catchPanicErr)
 		}
+		err = joinErrors(err, d.onFinally("", d.panicConverter()))
 		if finally != nil {
-			finally()
+			err = joinErrors(err, finallyGuard(finally, nil, "", d, d.panicConverter()))
 		}
 		if catchPanicErr != nil {
 			panic(catchPanicErr)
 		}
 	}()
 
-	return fn()
+	d.onTryStart()
+	result, err = fn()
+	d.onTryEnd(err)
+	return
+}
+
+// TryCatchRErr 类似 TryCatchR，但 catch 可以恢复、替换或重新抛出错误，并给出兜底的返回值
+// catch 的返回值即为最终结果：返回 nil 错误表示已恢复，返回新的错误会替换原错误
+func TryCatchRErr[T any](fn func() (T, error), catch func(error) (T, error), finally func()) (result T, err error) {
+	var catchPanicErr any
+	d := loadDefaults()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = convertPanic(r, "", 1, d, d.panicConverter())
+			d.onCatch(err)
+			if catch != nil {
+				result, err, catchPanicErr = typedCatchGuard(catch, err)
+			}
+		} else if err != nil && catch != nil {
+			d.onCatch(err)
+			result, err, catchPanicErr = typedCatchGuard(catch, err)
+		}
+		if catchPanicErr != nil {
+			d.onCatchPanic(catchPanicErr)
+		}
+		err = joinErrors(err, d.onFinally("", d.panicConverter()))
+		if finally != nil {
+			err = joinErrors(err, finallyGuard(finally, nil, "", d, d.panicConverter()))
+		}
+		if catchPanicErr != nil {
+			panic(catchPanicErr)
+		}
+	}()
+
+	d.onTryStart()
+	result, err = fn()
+	d.onTryEnd(err)
+	return
+}
+
+// TryOrElse 执行 fn，失败（返回错误或发生 panic）时返回 fallback，第二个返回值表示是否使用了兜底值
+// 失败时与 TryCatchRErr 一样触发 OnCatch 等默认钩子，可据此统计降级的响应
+func TryOrElse[T any](fn func() (T, error), fallback T) (T, bool) {
+	used := false
+	result, _ := TryCatchRErr(fn, func(error) (T, error) {
+		used = true
+		return fallback, nil
+	}, nil)
+	return result, used
+}
+
+// TryOrElseFunc 执行 fn，失败时调用 fallback 根据错误计算兜底值，第二个返回值表示是否调用了 fallback
+// fallback 返回的错误即为最终错误：返回 nil 表示降级成功；fallback 为 nil 时直接返回 fn 的错误。
+// fallback 自身的 panic 与 TryCatchRErr 中 catch 的 panic 一样向上传播
+func TryOrElseFunc[T any](fn func() (T, error), fallback func(error) (T, error)) (T, bool, error) {
+	if fallback == nil {
+		result, err := TryWithResult(fn)
+		return result, false, err
+	}
+	used := false
+	result, err := TryCatchRErr(fn, func(err error) (T, error) {
+		used = true
+		return fallback(err)
+	}, nil)
+	return result, used, err
+}
+
+// typedCatchGuard 在隔离环境中执行带返回值的 catch 函数，捕获 catch 内部的 panic 并返回
+func typedCatchGuard[T any](catch func(error) (T, error), err error) (result T, caught error, panicVal any) {
+	defer func() { panicVal = recover() }()
+	result, caught = catch(err)
+	return
 }
@@ -44,7 +44,7 @@ func TestTryWithResult_WithTypedError(t *testing.T) {
 	})
 
 	assert.Error(t, err)
-	assert.Equal(t, myErr, err, "should preserve original error from panic")
+	assert.ErrorIs(t, err, myErr, "should preserve original error from panic")
 	assert.Equal(t, 0, result)
 }
 
@@ -224,6 +224,33 @@ func TestTryCatchR_FinallyAlwaysRuns(t *testing.T) {
 	assert.Equal(t, 3, finallyCount, "finally should run in all three scenarios")
 }
 
+func TestGenerics_FinallyPanicJoined(t *testing.T) {
+	tryErr := errors.New("try failed")
+	finallyPanics := func() { panic("finally boom") }
+	checks := map[string]func() (int, error){
+		"TryWithResultAndFinally": func() (int, error) {
+			return TryWithResultAndFinally(func() (int, error) { return 1, tryErr }, finallyPanics)
+		},
+		"TryCatchR": func() (int, error) {
+			return TryCatchR(func() (int, error) { return 1, tryErr }, func(error) {}, finallyPanics)
+		},
+		"TryCatchRErr": func() (int, error) {
+			return TryCatchRErr(func() (int, error) { return 1, tryErr }, func(err error) (int, error) { return 2, err }, finallyPanics)
+		},
+	}
+
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			assert.NotPanics(t, func() { _, err = check() })
+			assert.ErrorIs(t, err, tryErr, "the pending error should be kept")
+			var pe *PanicError
+			assert.ErrorAs(t, err, &pe)
+			assert.Equal(t, "finally boom", pe.Value)
+		})
+	}
+}
+
 func TestTryCatchR_NilCatchNilFinally(t *testing.T) {
 	result, err := TryCatchR(
 		func() (int, error) {
@@ -277,3 +304,145 @@ func TestTryCatchR_CatchPanicAfterFnPanic(t *testing.T) {
 
 	assert.Equal(t, 1, finallyCount, "finally must be called exactly once when both fn and catch panic")
 }
+
+func TestTryCatchRErr_Fallback(t *testing.T) {
+	result, err := TryCatchRErr(
+		func() (int, error) {
+			return 0, errors.New("compute error")
+		},
+		func(err error) (int, error) {
+			return -1, nil
+		},
+		nil,
+	)
+
+	assert.NoError(t, err, "catch should be able to recover the error")
+	assert.Equal(t, -1, result, "catch should supply the fallback value")
+}
+
+func TestTryCatchRErr_PanicReplaced(t *testing.T) {
+	domainErr := errors.New("domain error")
+	finallyCalled := false
+
+	result, err := TryCatchRErr(
+		func() (string, error) {
+			panic("boom")
+		},
+		func(err error) (string, error) {
+			var panicErr *PanicError
+			assert.ErrorAs(t, err, &panicErr)
+			return "partial", domainErr
+		},
+		func() {
+			finallyCalled = true
+		},
+	)
+
+	assert.Equal(t, domainErr, err)
+	assert.Equal(t, "partial", result)
+	assert.True(t, finallyCalled)
+}
+
+func TestTryCatchRErr_SuccessSkipsCatch(t *testing.T) {
+	result, err := TryCatchRErr(
+		func() (int, error) {
+			return 42, nil
+		},
+		func(err error) (int, error) {
+			t.Error("catch should not be called on success")
+			return 0, nil
+		},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestTryCatchRErr_CatchPanic(t *testing.T) {
+	finallyCalled := false
+
+	assert.Panics(t, func() {
+		TryCatchRErr(
+			func() (int, error) {
+				return 0, errors.New("error")
+			},
+			func(err error) (int, error) {
+				panic("panic in catch")
+			},
+			func() {
+				finallyCalled = true
+			},
+		)
+	})
+	assert.True(t, finallyCalled, "finally should run even when catch panics")
+}
+
+func TestTryOrElse(t *testing.T) {
+	v, used := TryOrElse(func() (int, error) { return 42, nil }, -1)
+	assert.Equal(t, 42, v)
+	assert.False(t, used)
+
+	v, used = TryOrElse(func() (int, error) { return 0, errors.New("boom") }, -1)
+	assert.Equal(t, -1, v)
+	assert.True(t, used)
+
+	v, used = TryOrElse(func() (int, error) { panic("boom") }, -1)
+	assert.Equal(t, -1, v)
+	assert.True(t, used, "panics fall back too")
+}
+
+func TestTryOrElseFunc(t *testing.T) {
+	myErr := errors.New("boom")
+
+	v, used, err := TryOrElseFunc(func() (string, error) { return "live", nil }, func(error) (string, error) {
+		return "cached", nil
+	})
+	assert.Equal(t, "live", v)
+	assert.False(t, used)
+	assert.NoError(t, err)
+
+	var got error
+	v, used, err = TryOrElseFunc(func() (string, error) { return "", myErr }, func(err error) (string, error) {
+		got = err
+		return "cached", nil
+	})
+	assert.Equal(t, "cached", v)
+	assert.True(t, used)
+	assert.NoError(t, err)
+	assert.Equal(t, myErr, got)
+
+	errNoCache := errors.New("no cache")
+	_, used, err = TryOrElseFunc(func() (string, error) { panic("boom") }, func(err error) (string, error) {
+		assert.ErrorAs(t, err, new(*PanicError))
+		return "", errNoCache
+	})
+	assert.True(t, used)
+	assert.Equal(t, errNoCache, err)
+}
+
+func TestTryOrElseFunc_NilFallback(t *testing.T) {
+	myErr := errors.New("boom")
+	_, used, err := TryOrElseFunc[int](func() (int, error) { return 0, myErr }, nil)
+	assert.False(t, used)
+	assert.Equal(t, myErr, err)
+}
+
+func TestTryOrElseFunc_FallbackPanic(t *testing.T) {
+	assert.PanicsWithValue(t, "fallback boom", func() {
+		_, _, _ = TryOrElseFunc(func() (int, error) { return 0, errors.New("boom") }, func(error) (int, error) {
+			panic("fallback boom")
+		})
+	})
+}
+
+func TestTryOrElse_DefaultHooks(t *testing.T) {
+	resetDefaults(t)
+	var catches int
+	SetDefaultHooks(Hooks{OnCatch: func(error) { catches++ }})
+
+	_, _ = TryOrElse(func() (int, error) { return 1, nil }, 0)
+	_, _ = TryOrElse(func() (int, error) { return 0, errors.New("boom") }, 0)
+
+	assert.Equal(t, 1, catches, "degraded responses are visible to OnCatch")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -56,8 +57,8 @@ func TestTryCatchBlock_Do(t *testing.T) {
 			finallyHandler: nil,
 		},
 		{
-			name:        "Finally function",
-			tryFunction: func() error { return nil },
+			name:         "Finally function",
+			tryFunction:  func() error { return nil },
 			catchHandler: nil,
 			finallyHandler: func() {
 				finallyCalled = true
@@ -114,8 +115,9 @@ func TestTryCatchBlock_Do(t *testing.T) {
 			},
 			catchHandler: func(err error) {
 				assert.Equal(t, "custom error", err.Error())
-				customErr, ok := err.(customError)
-				assert.True(t, ok, "error should be of type customError")
+				var customErr customError
+				ok := errors.As(err, &customErr)
+				assert.True(t, ok, "error chain should contain customError")
 				assert.Equal(t, "custom error", customErr.errorMessage)
 			},
 			finallyHandler: nil,
@@ -387,7 +389,7 @@ func TestTryCatchBlock_ConcurrentWithPool(t *testing.T) {
 }
 
 func TestTryCatchBlock_FinallyPanic(t *testing.T) {
-	t.Run("Finally panic propagates", func(t *testing.T) {
+	t.Run("Finally panic becomes error", func(t *testing.T) {
 		tryCatch := New().
 			Try(func() error {
 				return nil
@@ -396,15 +398,21 @@ func TestTryCatchBlock_FinallyPanic(t *testing.T) {
 				panic("panic in finally")
 			})
 
-		assert.Panics(t, func() {
-			tryCatch.Do()
+		var err error
+		assert.NotPanics(t, func() {
+			err = tryCatch.Do()
 		})
+
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "panic in finally", panicErr.Value)
 	})
 
 	t.Run("Finally panic with error in try", func(t *testing.T) {
+		tryErr := errors.New("error in try")
 		tryCatch := New().
 			Try(func() error {
-				return errors.New("error in try")
+				return tryErr
 			}).
 			Catch(func(err error) {
 			}).
@@ -412,9 +420,15 @@ func TestTryCatchBlock_FinallyPanic(t *testing.T) {
 				panic("panic in finally")
 			})
 
-		assert.Panics(t, func() {
-			tryCatch.Do()
+		var err error
+		assert.NotPanics(t, func() {
+			err = tryCatch.Do()
 		})
+
+		assert.ErrorIs(t, err, tryErr, "try error should not be lost")
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr, "finally panic should be joined with try error")
+		assert.Equal(t, "panic in finally", panicErr.Value)
 	})
 
 	t.Run("Finally panic after catch panic", func(t *testing.T) {
@@ -429,9 +443,102 @@ func TestTryCatchBlock_FinallyPanic(t *testing.T) {
 				panic("panic in finally")
 			})
 
-		assert.Panics(t, func() {
+		assert.PanicsWithValue(t, "panic in catch", func() {
 			tryCatch.Do()
-		})
+		}, "catch panic should not be replaced by finally panic")
+	})
+
+	t.Run("OnFinally panic becomes error", func(t *testing.T) {
+		finallyCalled := false
+		tryCatch := New().
+			ApplyOptions(WithHooks(Hooks{
+				OnFinally: func() { panic("panic in hook") },
+			})).
+			Try(func() error {
+				return nil
+			}).
+			Finally(func() {
+				finallyCalled = true
+			})
+
+		err := tryCatch.Do()
+
+		assert.Error(t, err)
+		assert.Equal(t, "panic in hook", err.Error())
+		assert.True(t, finallyCalled, "finally should run even when OnFinally panics")
+	})
+}
+
+func TestTryCatchBlock_FinallyErr(t *testing.T) {
+	closeErr := errors.New("close failed")
+
+	t.Run("Error from finally is returned", func(t *testing.T) {
+		err := New().
+			Try(func() error {
+				return nil
+			}).
+			FinallyErr(func() error {
+				return closeErr
+			}).
+			Do()
+
+		assert.Equal(t, closeErr, err)
+	})
+
+	t.Run("Error from finally is joined with try error", func(t *testing.T) {
+		tryErr := errors.New("write failed")
+		err := New().
+			Try(func() error {
+				return tryErr
+			}).
+			FinallyErr(func() error {
+				return closeErr
+			}).
+			Do()
+
+		assert.ErrorIs(t, err, tryErr)
+		assert.ErrorIs(t, err, closeErr)
+	})
+
+	t.Run("Error from finally survives swallowing catch", func(t *testing.T) {
+		err := New().
+			Try(func() error {
+				return errors.New("handled")
+			}).
+			CatchErr(func(err error) error {
+				return nil
+			}).
+			FinallyErr(func() error {
+				return closeErr
+			}).
+			Do()
+
+		assert.Equal(t, closeErr, err)
+	})
+
+	t.Run("Nil error from finally", func(t *testing.T) {
+		err := New().
+			Try(func() error {
+				return nil
+			}).
+			FinallyErr(func() error {
+				return nil
+			}).
+			Do()
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Last setter wins", func(t *testing.T) {
+		tc := New().
+			FinallyErr(func() error { return closeErr }).
+			Finally(func() {})
+		assert.Nil(t, tc.finallyErr)
+		assert.NoError(t, tc.Do())
+
+		tc.FinallyErr(func() error { return closeErr })
+		assert.Nil(t, tc.finally)
+		assert.Equal(t, closeErr, tc.Do())
 	})
 }
 
@@ -464,18 +571,25 @@ func TestTryCatchBlock_Do_PanicWithErrorType(t *testing.T) {
 		Do()
 
 	assert.Error(t, err)
-	assert.Equal(t, myErr, err, "panic with error type should preserve original error")
-	assert.Equal(t, myErr, caughtErr)
+	assert.ErrorIs(t, err, myErr, "panic with error type should preserve original error")
+	assert.ErrorIs(t, caughtErr, myErr)
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "panic should be reported as *PanicError")
+	assert.Equal(t, myErr, panicErr.Value)
 }
 
 func TestTryCatchBlock_Reset_ClearsAllFields(t *testing.T) {
 	tc := New()
 	tc.name = "my-block"
-	tc.hooks = Hooks{OnTryStart: func() {}, OnTryEnd: func(error) {}, OnCatch: func(error){}, OnFinally: func(){}}
+	tc.hooks = Hooks{OnTryStart: func() {}, OnTryEnd: func(error) {}, OnCatch: func(error) {}, OnFinally: func() {}}
 	tc.ctx = context.Background()
+	tc.ApplyOptions(WithObserver(&recordingObserver{}), WithCircuitBreaker(NewBreaker("reset", BreakerConfig{})), WithBulkhead(NewBulkhead(1, 0, 0)))
 	tc.Try(func() error { return nil }).
+		CatchIs(context.Canceled, func(error) {}).
 		Catch(func(error) {}).
 		Finally(func() {})
+	_ = tc.Do()
 
 	tc.Reset()
 
@@ -484,7 +598,14 @@ func TestTryCatchBlock_Reset_ClearsAllFields(t *testing.T) {
 	assert.Equal(t, "", tc.name, "name should be empty after Reset")
 	assert.Nil(t, tc.try, "try should be nil after Reset")
 	assert.Nil(t, tc.catch, "catch should be nil after Reset")
+	assert.Empty(t, tc.clauses, "catch clauses should be empty after Reset")
 	assert.Nil(t, tc.finally, "finally should be nil after Reset")
+	assert.Nil(t, tc.finallyErr, "finallyErr should be nil after Reset")
+	assert.Empty(t, tc.options().observers, "observers should be empty after Reset")
+	assert.Equal(t, ExecInfo{}, tc.options().info, "exec info should be zero value after Reset")
+	assert.Nil(t, tc.options().breaker, "breaker should be nil after Reset")
+	assert.Nil(t, tc.options().bulkhead, "bulkhead should be nil after Reset")
+	assert.Nil(t, tc.scope, "scope should be nil after Reset")
 }
 
 func TestTryCatchBlock_Do_ContextCancelled_FinallyExecutes(t *testing.T) {
@@ -659,3 +780,49 @@ func TestTryCatchBlock_TryCtx_Reset(t *testing.T) {
 
 	assert.Nil(t, tc.tryCtx, "tryCtx should be nil after Reset")
 }
+
+func TestTryCatchBlock_Goexit(t *testing.T) {
+	var (
+		caught        error
+		tryEndErr     error
+		finallyCalled bool
+		afterGoexit   bool
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = New().
+			ApplyOptions(WithHooks(Hooks{
+				OnTryEnd: func(err error) { tryEndErr = err },
+			})).
+			Try(func() error {
+				runtime.Goexit()
+				return nil
+			}).
+			Catch(func(err error) { caught = err }).
+			Finally(func() { finallyCalled = true }).
+			Do()
+		afterGoexit = true
+	}()
+	<-done
+
+	assert.ErrorIs(t, caught, ErrGoexit, "catch should receive ErrGoexit")
+	assert.ErrorIs(t, tryEndErr, ErrGoexit, "OnTryEnd should receive ErrGoexit")
+	assert.True(t, finallyCalled, "finally should run on Goexit")
+	assert.False(t, afterGoexit, "goroutine should still exit")
+}
+
+func TestTryCatchBlock_Goexit_EnforceDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := NewWithOptions(WithContext(ctx), WithEnforceDeadline()).
+		Try(func() error {
+			runtime.Goexit()
+			return nil
+		}).
+		Do()
+
+	assert.ErrorIs(t, err, ErrGoexit, "detached try should report ErrGoexit")
+}
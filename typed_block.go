@@ -0,0 +1,166 @@
+package gotrycatch
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// TypedBlock 是带泛型返回值的 try-catch-finally 块，提供与 TryCatchBlock 相同的链式调用、选项与对象池复用
+// 与 TryCatchBlock 一样不是并发安全的，每个实例在同一时刻只应在一个 goroutine 中使用
+type TypedBlock[T any] struct {
+	block    TryCatchBlock                    // 实际执行流程的块
+	try      func() (T, error)                // 待执行的函数
+	tryCtx   func(context.Context) (T, error) // 上下文感知的 try 函数，与 try 互斥
+	catchErr func(error) (T, error)           // 可给出兜底返回值的错误处理函数
+	result   T                                // 本次执行的结果
+	caught   bool                             // 本次执行是否由 catchErr 给出了结果
+	run      func(context.Context) error      // 缓存的 runTry 方法值，避免每次设置 try 时分配
+	runPlain func() error                     // 缓存的 runPlainTry 方法值，try 不接收 context 时使用，不需要准备 Scope
+	recover  func(error) error                // 缓存的 runCatch 方法值
+}
+
+// NewTyped 创建一个 TypedBlock 实例并应用提供的选项
+func NewTyped[T any](opts ...Option) *TypedBlock[T] {
+	tb := &TypedBlock[T]{}
+	tb.init()
+	tb.block.ApplyOptions(opts...)
+	return tb
+}
+
+// init 缓存方法值，使零值的 TypedBlock 也可以使用
+func (tb *TypedBlock[T]) init() {
+	if tb.run == nil {
+		tb.run = tb.runTry
+		tb.runPlain = tb.runPlainTry
+		tb.recover = tb.runCatch
+	}
+}
+
+// ApplyOptions 将提供的选项应用到内部的块
+func (tb *TypedBlock[T]) ApplyOptions(opts ...Option) *TypedBlock[T] {
+	tb.block.ApplyOptions(opts...)
+	return tb
+}
+
+// Try 设置待执行的函数
+func (tb *TypedBlock[T]) Try(try func() (T, error)) *TypedBlock[T] {
+	tb.init()
+	tb.try, tb.tryCtx = try, nil
+	tb.block.try, tb.block.tryCtx = tb.runPlain, nil
+	return tb
+}
+
+// TryCtx 设置上下文感知的 try 函数，与 Try 互斥，后设置的生效
+func (tb *TypedBlock[T]) TryCtx(try func(context.Context) (T, error)) *TypedBlock[T] {
+	tb.init()
+	tb.try, tb.tryCtx = nil, try
+	tb.block.try, tb.block.tryCtx = nil, tb.run
+	return tb
+}
+
+// Catch 设置错误处理函数，Do 仍返回原错误与 try 给出的结果。与 CatchErr 互斥，后设置的生效
+func (tb *TypedBlock[T]) Catch(catch func(error)) *TypedBlock[T] {
+	tb.catchErr = nil
+	tb.block.Catch(catch)
+	return tb
+}
+
+// CatchErr 设置可以给出兜底返回值的错误处理函数，其返回值即为 Do 的结果：
+// 返回 nil 错误表示已恢复，返回新的错误会替换原错误。与 Catch 互斥，后设置的生效
+func (tb *TypedBlock[T]) CatchErr(catch func(error) (T, error)) *TypedBlock[T] {
+	tb.init()
+	tb.catchErr = catch
+	tb.block.CatchErr(tb.recover)
+	return tb
+}
+
+// Finally 设置清理函数，语义与 TryCatchBlock.Finally 相同
+func (tb *TypedBlock[T]) Finally(finally func()) *TypedBlock[T] {
+	tb.block.Finally(finally)
+	return tb
+}
+
+// FinallyErr 设置可返回错误的清理函数，语义与 TryCatchBlock.FinallyErr 相同
+func (tb *TypedBlock[T]) FinallyErr(finally func() error) *TypedBlock[T] {
+	tb.block.FinallyErr(finally)
+	return tb
+}
+
+// Reset 清理块的状态，用于对象池复用
+func (tb *TypedBlock[T]) Reset() {
+	var zero T
+	tb.block.Reset()
+	tb.try = nil
+	tb.tryCtx = nil
+	tb.catchErr = nil
+	tb.result = zero
+	tb.caught = false
+}
+
+// Do 执行 try-catch-finally 流程，返回 try 的结果与错误
+// try 失败时返回 try 给出的结果（发生 panic 时为零值）；使用 CatchErr 时返回处理函数给出的结果与错误
+func (tb *TypedBlock[T]) Do() (result T, err error) {
+	var zero T
+	tb.result, tb.caught = zero, false
+	if tb.block.options().enforceDeadline && (tb.try != nil || tb.tryCtx != nil) {
+		return tb.doDetached()
+	}
+	err = tb.block.Do()
+	result, tb.result = tb.result, zero
+	return result, err
+}
+
+// doDetached 在截止时间强制模式下执行
+// 被放弃的 try 会在后台继续运行，因此结果写入本次调用独有的变量，避免与调用方或后续的执行竞争
+func (tb *TypedBlock[T]) doDetached() (result T, err error) {
+	var cell atomic.Pointer[T]
+	try, tryCtx := tb.try, tb.tryCtx
+	blockTry, blockTryCtx := tb.block.try, tb.block.tryCtx
+	tb.block.try = nil
+	tb.block.tryCtx = func(ctx context.Context) error {
+		cell.Store(nil)
+		v, err := callTyped(ctx, try, tryCtx)
+		cell.Store(&v)
+		return err
+	}
+	defer func() { tb.block.try, tb.block.tryCtx = blockTry, blockTryCtx }()
+
+	err = tb.block.Do()
+	var zero T
+	if tb.caught {
+		result = tb.result
+	} else if p := cell.Load(); p != nil {
+		result = *p
+	}
+	tb.result = zero
+	return result, err
+}
+
+// runTry 执行 try 并保存结果
+// 每次尝试前先清空结果，避免重试时发生 panic 的尝试沿用之前尝试的结果
+func (tb *TypedBlock[T]) runTry(ctx context.Context) (err error) {
+	var zero T
+	tb.result = zero
+	tb.result, err = callTyped(ctx, tb.try, tb.tryCtx)
+	return err
+}
+
+// runPlainTry 执行不接收 context 的 try 并保存结果
+func (tb *TypedBlock[T]) runPlainTry() error {
+	return tb.runTry(nil)
+}
+
+// runCatch 执行 catchErr 并以其结果替换 try 的结果
+func (tb *TypedBlock[T]) runCatch(err error) error {
+	tb.result, err = tb.catchErr(err)
+	tb.caught = true
+	return err
+}
+
+// callTyped 执行 try 或 tryCtx，二者同时设置时 try 优先
+func callTyped[T any](ctx context.Context, try func() (T, error), tryCtx func(context.Context) (T, error)) (T, error) {
+	if try != nil {
+		return try()
+	}
+	return tryCtx(ctx)
+}
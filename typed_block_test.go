@@ -0,0 +1,215 @@
+package gotrycatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedBlock_Success(t *testing.T) {
+	var finallyCalled bool
+
+	v, err := NewTyped[int]().
+		Try(func() (int, error) { return 42, nil }).
+		Catch(func(error) { t.Fatal("catch should not run") }).
+		Finally(func() { finallyCalled = true }).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+	assert.True(t, finallyCalled)
+}
+
+func TestTypedBlock_Error(t *testing.T) {
+	myErr := errors.New("boom")
+	var caught error
+
+	v, err := NewTyped[string]().
+		Try(func() (string, error) { return "partial", myErr }).
+		Catch(func(err error) { caught = err }).
+		Do()
+
+	assert.Equal(t, myErr, err)
+	assert.Equal(t, myErr, caught)
+	assert.Equal(t, "partial", v, "result from try is kept")
+}
+
+func TestTypedBlock_Panic(t *testing.T) {
+	v, err := NewTyped[int]().
+		Try(func() (int, error) { panic("boom") }).
+		Do()
+
+	assert.Zero(t, v)
+	assert.ErrorAs(t, err, new(*PanicError))
+}
+
+func TestTypedBlock_CatchErr(t *testing.T) {
+	v, err := NewTyped[int]().
+		Try(func() (int, error) { panic("boom") }).
+		CatchErr(func(error) (int, error) { return -1, nil }).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, -1, v)
+
+	errReplaced := errors.New("replaced")
+	v, err = NewTyped[int]().
+		Try(func() (int, error) { return 0, errors.New("boom") }).
+		CatchErr(func(error) (int, error) { return 7, errReplaced }).
+		Do()
+	assert.Equal(t, errReplaced, err)
+	assert.Equal(t, 7, v)
+}
+
+func TestTypedBlock_TryCtxAndOptions(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	var catches int
+
+	tb := NewTyped[string](
+		WithContext(ctx),
+		WithName("typed"),
+		WithHooks(Hooks{OnCatch: func(error) { catches++ }}),
+	)
+	v, err := tb.TryCtx(func(ctx context.Context) (string, error) {
+		return ctx.Value(key{}).(string), nil
+	}).Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "value", v)
+	assert.Equal(t, "typed", tb.block.Name())
+
+	_, _ = tb.TryCtx(func(context.Context) (string, error) { return "", errors.New("boom") }).
+		Catch(func(error) {}).
+		Do()
+	assert.Equal(t, 1, catches)
+}
+
+func TestTypedBlock_Retry(t *testing.T) {
+	attempts := 0
+
+	v, err := NewTyped[int](WithRetry(RetryPolicy{MaxAttempts: 3})).
+		Try(func() (int, error) {
+			attempts++
+			if attempts < 3 {
+				return 0, errors.New("transient")
+			}
+			return attempts, nil
+		}).
+		Do()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+}
+
+func TestTypedBlock_RetryPanicDropsEarlierResult(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"inline":   nil,
+		"detached": {WithTimeout(time.Second), WithEnforceDeadline()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			policy := RetryPolicy{MaxAttempts: 2, Retryable: func(error) bool { return true }}
+
+			v, err := NewTyped[int](append(opts, WithRetry(policy))...).
+				Try(func() (int, error) {
+					attempts++
+					if attempts == 1 {
+						return 42, errors.New("transient")
+					}
+					panic("p")
+				}).
+				Do()
+
+			var panicErr *PanicError
+			assert.ErrorAs(t, err, &panicErr)
+			assert.Equal(t, 0, v, "a panicking final attempt should not return the result of an earlier attempt")
+		})
+	}
+}
+
+func TestTypedBlock_EnforceDeadline(t *testing.T) {
+	v, err := NewTyped[int](WithTimeout(time.Second), WithEnforceDeadline()).
+		Try(func() (int, error) { return 1, nil }).
+		Do()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	release := make(chan struct{})
+	defer close(release)
+	v, err = NewTyped[int](WithTimeout(10*time.Millisecond), WithEnforceDeadline()).
+		Try(func() (int, error) {
+			<-release
+			return 2, nil
+		}).
+		CatchErr(func(err error) (int, error) { return -1, err }).
+		Do()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, -1, v)
+}
+
+func TestTypedBlock_LastSetterWins(t *testing.T) {
+	tb := NewTyped[int]().
+		Try(func() (int, error) { return 1, nil }).
+		TryCtx(func(context.Context) (int, error) { return 2, nil })
+	v, _ := tb.Do()
+	assert.Equal(t, 2, v)
+
+	v, err := tb.Try(func() (int, error) { return 0, errors.New("boom") }).
+		CatchErr(func(error) (int, error) { return -1, nil }).
+		Catch(func(error) {}).
+		Do()
+	assert.Error(t, err, "Catch replaces CatchErr")
+	assert.Zero(t, v)
+}
+
+func TestTypedBlock_ZeroValue(t *testing.T) {
+	var tb TypedBlock[int]
+	v, err := tb.Try(func() (int, error) { return 5, nil }).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, v)
+}
+
+func TestTypedBlock_NilTry(t *testing.T) {
+	v, err := NewTyped[int]().Do()
+	assert.NoError(t, err)
+	assert.Zero(t, v)
+}
+
+func TestTypedBlock_ResetAndPool(t *testing.T) {
+	pool := sync.Pool{New: func() any { return NewTyped[int]() }}
+
+	tb := pool.Get().(*TypedBlock[int])
+	tb.ApplyOptions(WithName("pooled")).
+		Try(func() (int, error) { return 1, errors.New("boom") }).
+		CatchErr(func(error) (int, error) { return 2, nil })
+	v, err := tb.Do()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	tb.Reset()
+	assert.Nil(t, tb.try)
+	assert.Nil(t, tb.catchErr)
+	assert.Zero(t, tb.result)
+	assert.Equal(t, "", tb.block.Name())
+	pool.Put(tb)
+
+	tb = pool.Get().(*TypedBlock[int])
+	v, err = tb.Try(func() (int, error) { return 3, nil }).Do()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+}
+
+func TestTypedBlock_PoolReuseNoAllocs(t *testing.T) {
+	tb := NewTyped[int]()
+	try := func() (int, error) { return 1, nil }
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = tb.Try(try).Do()
+		tb.Reset()
+	})
+	assert.Zero(t, allocs)
+}